@@ -0,0 +1,172 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"aesc-client/parse"
+)
+
+const (
+	Solved     = "solved"
+	Place      = "place"
+	FirstSolve = "first_solve"
+)
+
+type Change struct {
+	Kind     string `json:"kind"`
+	Team     string `json:"team"`
+	Problem  string `json:"problem,omitempty"`
+	Member   string `json:"member,omitempty"`
+	Place    int    `json:"place,omitempty"`
+	OldPlace int    `json:"old_place,omitempty"`
+	Time     int    `json:"time,omitempty"`
+}
+
+func (c Change) Text() string {
+	switch c.Kind {
+	case Solved:
+		who := c.Team
+		if c.Member != "" {
+			who = c.Member + " (" + c.Team + ")"
+		}
+		if c.Time > 0 {
+			return fmt.Sprintf("%s got AC on %s at %d:%02d", who, c.Problem, c.Time/60, c.Time%60)
+		}
+		return fmt.Sprintf("%s got AC on %s", who, c.Problem)
+	case Place:
+		if c.OldPlace == 0 {
+			return fmt.Sprintf("%s is now #%d", c.Team, c.Place)
+		}
+		dir := "up"
+		if c.Place > c.OldPlace {
+			dir = "down"
+		}
+		return fmt.Sprintf("%s moved %s from #%d to #%d", c.Team, dir, c.OldPlace, c.Place)
+	case FirstSolve:
+		return fmt.Sprintf("first to solve %s: %s", c.Problem, c.Team)
+	}
+	return c.Kind
+}
+
+func Diff(prev, cur *parse.Standings, us string) []Change {
+	if prev == nil || cur == nil {
+		return nil
+	}
+	var out []Change
+	for _, p := range cur.Problems {
+		if solvers(prev, p) > 0 {
+			continue
+		}
+		for _, r := range cur.Rows {
+			if r.Results[p].Solved {
+				out = append(out, Change{Kind: FirstSolve, Team: r.Team, Problem: p, Time: r.Results[p].Time})
+			}
+		}
+	}
+	if us == "" {
+		return out
+	}
+	now, was := cur.Find(us), prev.Find(us)
+	if now == nil {
+		return out
+	}
+	for _, p := range cur.Problems {
+		res := now.Results[p]
+		if !res.Solved || was != nil && was.Results[p].Solved {
+			continue
+		}
+		out = append(out, Change{Kind: Solved, Team: now.Team, Problem: p, Time: res.Time})
+	}
+	if was == nil || was.Place != now.Place {
+		c := Change{Kind: Place, Team: now.Team, Place: now.Place}
+		if was != nil {
+			c.OldPlace = was.Place
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func solvers(st *parse.Standings, problem string) int {
+	n := 0
+	for _, r := range st.Rows {
+		if r.Results[problem].Solved {
+			n++
+		}
+	}
+	return n
+}
+
+func CheckFormat(format string) error {
+	switch format {
+	case "", "slack", "telegram", "matrix", "json":
+		return nil
+	}
+	return fmt.Errorf("unknown webhook format %q: use slack, telegram, matrix or json", format)
+}
+
+type Webhook struct {
+	URL    string
+	Format string
+	ChatID string
+	Client *http.Client
+}
+
+func (w *Webhook) payload(changes []Change) (any, error) {
+	if err := CheckFormat(w.Format); err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.Text())
+	}
+	text := strings.Join(lines, "\n")
+	switch w.Format {
+	case "", "slack":
+		return map[string]string{"text": text}, nil
+	case "telegram":
+		return map[string]string{"chat_id": w.ChatID, "text": text}, nil
+	case "matrix":
+		return map[string]string{"msgtype": "m.notice", "body": text}, nil
+	}
+	return map[string]any{"text": text, "changes": changes}, nil
+}
+
+func (w *Webhook) Post(ctx context.Context, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	v, err := w.payload(changes)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned %s: %s", w.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
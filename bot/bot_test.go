@@ -0,0 +1,285 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"aesc-client/parse"
+)
+
+type received struct {
+	method      string
+	contentType string
+	body        map[string]any
+}
+
+func receiver(t *testing.T, status int) (*httptest.Server, *[]received) {
+	var got []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		var body map[string]any
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Errorf("payload is not a JSON object: %v: %s", err, b)
+		}
+		got = append(got, received{r.Method, r.Header.Get("Content-Type"), body})
+		w.WriteHeader(status)
+		io.WriteString(w, "bad token\n")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+var testChanges = []Change{
+	{Kind: Solved, Team: "us", Problem: "A", Member: "alice", Time: 75},
+	{Kind: Place, Team: "us", Place: 2, OldPlace: 5},
+}
+
+const testText = "alice (us) got AC on A at 1:15\nus moved up from #5 to #2"
+
+func TestWebhookPost(t *testing.T) {
+	tests := []struct {
+		format string
+		want   map[string]any
+	}{
+		{"slack", map[string]any{"text": testText}},
+		{"telegram", map[string]any{"chat_id": "42", "text": testText}},
+		{"matrix", map[string]any{"msgtype": "m.notice", "body": testText}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			srv, got := receiver(t, http.StatusOK)
+			w := &Webhook{URL: srv.URL, Format: tt.format, ChatID: "42", Client: srv.Client()}
+			if err := w.Post(context.Background(), testChanges); err != nil {
+				t.Fatalf("Post: %v", err)
+			}
+			if len(*got) != 1 {
+				t.Fatalf("receiver got %d requests, want 1", len(*got))
+			}
+			r := (*got)[0]
+			if r.method != "POST" || r.contentType != "application/json" {
+				t.Errorf("got %s with Content-Type %q, want POST with application/json", r.method, r.contentType)
+			}
+			if len(r.body) != len(tt.want) {
+				t.Errorf("payload %v, want %v", r.body, tt.want)
+			}
+			for k, v := range tt.want {
+				if r.body[k] != v {
+					t.Errorf("payload[%q] = %v, want %v", k, r.body[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPostJSON(t *testing.T) {
+	srv, got := receiver(t, http.StatusNoContent)
+	w := &Webhook{URL: srv.URL, Format: "json", Client: srv.Client()}
+	if err := w.Post(context.Background(), testChanges); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if len(*got) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(*got))
+	}
+	body := (*got)[0].body
+	if body["text"] != testText {
+		t.Errorf("text = %v, want %q", body["text"], testText)
+	}
+	changes, ok := body["changes"].([]any)
+	if !ok || len(changes) != len(testChanges) {
+		t.Fatalf("changes = %v, want %d entries", body["changes"], len(testChanges))
+	}
+	first, _ := changes[0].(map[string]any)
+	if first["kind"] != Solved || first["problem"] != "A" || first["member"] != "alice" {
+		t.Errorf("changes[0] = %v", first)
+	}
+}
+
+func TestWebhookPostNoChanges(t *testing.T) {
+	srv, got := receiver(t, http.StatusOK)
+	w := &Webhook{URL: srv.URL, Client: srv.Client()}
+	if err := w.Post(context.Background(), nil); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if len(*got) != 0 {
+		t.Errorf("receiver got %d requests for no changes, want 0", len(*got))
+	}
+}
+
+func TestWebhookPostError(t *testing.T) {
+	srv, got := receiver(t, http.StatusUnauthorized)
+	w := &Webhook{URL: srv.URL, Format: "slack", Client: srv.Client()}
+	err := w.Post(context.Background(), testChanges)
+	if err == nil {
+		t.Fatal("Post succeeded on a 401 reply")
+	}
+	if !strings.Contains(err.Error(), "401") || !strings.Contains(err.Error(), "bad token") {
+		t.Errorf("error %q should carry the status and the reply body", err)
+	}
+	if len(*got) != 1 {
+		t.Errorf("receiver got %d requests, want 1", len(*got))
+	}
+}
+
+func TestWebhookPostBadFormat(t *testing.T) {
+	srv, got := receiver(t, http.StatusOK)
+	w := &Webhook{URL: srv.URL, Format: "irc", Client: srv.Client()}
+	if err := w.Post(context.Background(), testChanges); err == nil {
+		t.Fatal("Post accepted an unknown format")
+	}
+	if len(*got) != 0 {
+		t.Errorf("receiver got %d requests for an unknown format, want 0", len(*got))
+	}
+}
+
+func standings(rows ...parse.StandingsRow) *parse.Standings {
+	return &parse.Standings{Problems: []string{"A", "B"}, Rows: rows}
+}
+
+func row(team string, place int, solved map[string]int) parse.StandingsRow {
+	r := parse.StandingsRow{Place: place, Team: team, Results: map[string]parse.ProblemResult{}}
+	for p, t := range solved {
+		r.Results[p] = parse.ProblemResult{Solved: true, Attempts: 1, Time: t}
+		r.Solved++
+	}
+	return r
+}
+
+func TestDiff(t *testing.T) {
+	base := standings(
+		row("team1", 1, map[string]int{"A": 10}),
+		row("us", 2, nil),
+		row("t2", 3, nil),
+	)
+	tests := []struct {
+		name string
+		prev *parse.Standings
+		cur  *parse.Standings
+		us   string
+		want []Change
+	}{
+		{
+			name: "first poll has nothing to compare",
+			prev: nil,
+			cur:  base,
+			us:   "us",
+			want: nil,
+		},
+		{
+			name: "first solve by a team that had no row before",
+			prev: base,
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("new", 2, map[string]int{"B": 42}),
+				row("us", 3, nil),
+				row("t2", 4, nil),
+			),
+			want: []Change{{Kind: FirstSolve, Team: "new", Problem: "B", Time: 42}},
+		},
+		{
+			name: "no first solve once prev has a solver",
+			prev: base,
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("t2", 2, map[string]int{"A": 50}),
+				row("us", 3, nil),
+			),
+			want: nil,
+		},
+		{
+			name: "our solve moves us up",
+			prev: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("t2", 2, map[string]int{"A": 30}),
+				row("us", 3, nil),
+			),
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("us", 2, map[string]int{"A": 20}),
+				row("t2", 3, map[string]int{"A": 30}),
+			),
+			us: "US",
+			want: []Change{
+				{Kind: Solved, Team: "us", Problem: "A", Time: 20},
+				{Kind: Place, Team: "us", Place: 2, OldPlace: 3},
+			},
+		},
+		{
+			name: "others overtake us",
+			prev: base,
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("t2", 2, map[string]int{"A": 70}),
+				row("us", 3, nil),
+			),
+			us:   "us",
+			want: []Change{{Kind: Place, Team: "us", Place: 3, OldPlace: 2}},
+		},
+		{
+			name: "solved problems are not posted twice",
+			prev: standings(row("us", 1, map[string]int{"A": 5})),
+			cur:  standings(row("us", 1, map[string]int{"A": 5})),
+			us:   "us",
+			want: nil,
+		},
+		{
+			name: "our team missing from cur",
+			prev: base,
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10, "B": 90}),
+				row("t2", 2, nil),
+			),
+			us:   "us",
+			want: []Change{{Kind: FirstSolve, Team: "team1", Problem: "B", Time: 90}},
+		},
+		{
+			name: "our team appears",
+			prev: standings(row("team1", 1, map[string]int{"A": 10})),
+			cur: standings(
+				row("team1", 1, map[string]int{"A": 10}),
+				row("us", 2, nil),
+			),
+			us:   "us",
+			want: []Change{{Kind: Place, Team: "us", Place: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.prev, tt.cur, tt.us)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Diff = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("change %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChangeText(t *testing.T) {
+	tests := []struct {
+		c    Change
+		want string
+	}{
+		{Change{Kind: Solved, Team: "us", Problem: "A", Time: 75}, "us got AC on A at 1:15"},
+		{Change{Kind: Solved, Team: "us", Problem: "B", Member: "bob"}, "bob (us) got AC on B"},
+		{Change{Kind: Place, Team: "us", Place: 2, OldPlace: 5}, "us moved up from #5 to #2"},
+		{Change{Kind: Place, Team: "us", Place: 5, OldPlace: 2}, "us moved down from #2 to #5"},
+		{Change{Kind: Place, Team: "us", Place: 4}, "us is now #4"},
+		{Change{Kind: FirstSolve, Team: "t2", Problem: "C"}, "first to solve C: t2"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Text(); got != tt.want {
+			t.Errorf("%+v.Text() = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
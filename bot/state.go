@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"aesc-client/parse"
+)
+
+type State struct {
+	Last map[string]*parse.Standings `json:"last"`
+
+	path string
+}
+
+func LoadState(path string) (*State, error) {
+	s := &State{Last: map[string]*parse.Standings{}, path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if s.Last == nil {
+		s.Last = map[string]*parse.Standings{}
+	}
+	return s, nil
+}
+
+func (s *State) Save() error {
+	fdir := filepath.Dir(s.path)
+	err := os.MkdirAll(fdir, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", fdir, err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encode bot state: %w", err)
+	}
+	err = os.WriteFile(s.path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const StaleWarning = `111 - "Revalidation Failed"`
+
+type Rule struct {
+	Contains string
+	TTL      time.Duration
+}
+
+var DefaultRules = []Rule{
+	{"/text", 10 * time.Minute},
+	{"/problem", 30 * time.Second},
+	{"/motd", time.Minute},
+	{"ranking", 30 * time.Second},
+	{"", 0},
+}
+
+type Transport struct {
+	Dir   string
+	Base  http.RoundTripper
+	Rules []Rule
+
+	locks sync.Map
+}
+
+type entry struct {
+	URL          string      `json:"url"`
+	Stored       time.Time   `json:"stored"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Header       http.Header `json:"header"`
+	BodyHash     string      `json:"body_hash"`
+}
+
+func New(dir string, base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{Dir: dir, Base: base, Rules: DefaultRules}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.Base.RoundTrip(req)
+	}
+	key := t.key(req)
+	unlock := t.lock(key)
+	defer unlock()
+	e, body, hit := t.load(key)
+	revalidate := strings.Contains(req.Header.Get("Cache-Control"), "no-cache")
+	if hit && !revalidate && time.Since(e.Stored) < t.ttl(req.URL.String()) {
+		return e.response(req, body), nil
+	}
+	if hit {
+		req = req.Clone(req.Context())
+		if e.ETag != "" {
+			req.Header.Set("If-None-Match", e.ETag)
+		}
+		if e.LastModified != "" {
+			req.Header.Set("If-Modified-Since", e.LastModified)
+		}
+	}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		if hit {
+			stale := e.response(req, body)
+			stale.Header.Set("Warning", StaleWarning)
+			return stale, nil
+		}
+		return nil, err
+	}
+	if hit && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		e.Stored = time.Now()
+		t.store(key, e, body)
+		return e.response(req, body), nil
+	}
+	if resp.StatusCode != http.StatusOK || req.Method != http.MethodGet {
+		return resp, nil
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", req.URL, err)
+	}
+	e = &entry{
+		URL:          req.URL.String(),
+		Stored:       time.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       http.Header{},
+	}
+	for _, h := range []string{"Content-Type", "ETag", "Last-Modified"} {
+		if v := resp.Header.Get(h); v != "" {
+			e.Header.Set(h, v)
+		}
+	}
+	t.store(key, e, b)
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	return resp, nil
+}
+
+func (t *Transport) Clear() error {
+	err := os.RemoveAll(t.Dir)
+	if err != nil {
+		return fmt.Errorf("clear cache: %w", err)
+	}
+	return nil
+}
+
+func (t *Transport) ttl(u string) time.Duration {
+	for _, r := range t.Rules {
+		if strings.Contains(u, r.Contains) {
+			return r.TTL
+		}
+	}
+	return 0
+}
+
+func (t *Transport) key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Cookie") + "\n" + req.URL.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (t *Transport) lock(key string) func() {
+	m, _ := t.locks.LoadOrStore(key, new(sync.Mutex))
+	mu := m.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *Transport) load(key string) (*entry, []byte, bool) {
+	mb, err := os.ReadFile(filepath.Join(t.Dir, key+".json"))
+	if err != nil {
+		return nil, nil, false
+	}
+	var e entry
+	if json.Unmarshal(mb, &e) != nil {
+		return nil, nil, false
+	}
+	body, err := os.ReadFile(filepath.Join(t.Dir, key+".body"))
+	if err != nil || bodyHash(body) != e.BodyHash {
+		return nil, nil, false
+	}
+	return &e, body, true
+}
+
+func (t *Transport) store(key string, e *entry, body []byte) {
+	if err := os.MkdirAll(t.Dir, 0o700); err != nil {
+		return
+	}
+	e.BodyHash = bodyHash(body)
+	mb, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if t.writeFile(key+".body", body) != nil {
+		return
+	}
+	t.writeFile(key+".json", mb)
+}
+
+func (t *Transport) writeFile(name string, data []byte) error {
+	f, err := os.CreateTemp(t.Dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(t.Dir, name))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+func (e *entry) response(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
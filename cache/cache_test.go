@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, c *http.Client, url string, header ...string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return resp, string(b)
+}
+
+func TestRevalidate(t *testing.T) {
+	var hits, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "statement v1")
+	}))
+	defer srv.Close()
+	tr := New(t.TempDir(), nil)
+	tr.Rules = []Rule{{"", time.Hour}}
+	c := &http.Client{Transport: tr}
+
+	_, body := get(t, c, srv.URL)
+	if body != "statement v1" || hits.Load() != 1 {
+		t.Fatalf("first GET: body %q, %d hits", body, hits.Load())
+	}
+	_, body = get(t, c, srv.URL)
+	if body != "statement v1" || hits.Load() != 1 {
+		t.Fatalf("fresh entry: body %q, %d hits, want no request", body, hits.Load())
+	}
+	resp, body := get(t, c, srv.URL, "Cache-Control", "no-cache")
+	if hits.Load() != 2 || notModified.Load() != 1 {
+		t.Fatalf("no-cache: %d hits, %d not modified, want a conditional request", hits.Load(), notModified.Load())
+	}
+	if resp.StatusCode != http.StatusOK || body != "statement v1" || resp.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("304 answered with %d %q %v, want the cached 200", resp.StatusCode, body, resp.Header)
+	}
+}
+
+func TestRevalidateChanged(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := fmt.Sprintf(`"v%d"`, version.Load())
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, "statement %s", etag)
+	}))
+	defer srv.Close()
+	tr := New(t.TempDir(), nil)
+	tr.Rules = []Rule{{"", time.Hour}}
+	c := &http.Client{Transport: tr}
+
+	get(t, c, srv.URL)
+	version.Store(2)
+	_, body := get(t, c, srv.URL, "Cache-Control", "no-cache")
+	if body != `statement "v2"` {
+		t.Fatalf("changed statement: got %q", body)
+	}
+	_, body = get(t, c, srv.URL)
+	if body != `statement "v2"` {
+		t.Errorf("cache kept the old body: got %q", body)
+	}
+}
+
+func TestConcurrentStore(t *testing.T) {
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := int(n.Add(1))
+		w.Header().Set("ETag", strconv.Itoa(v))
+		io.WriteString(w, strings.Repeat(strconv.Itoa(v%10), 1000*v))
+	}))
+	defer srv.Close()
+	tr := New(t.TempDir(), nil)
+	tr.Rules = []Rule{{"", 0}}
+	c := &http.Client{Transport: tr}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				resp, body := get(t, c, srv.URL)
+				v, err := strconv.Atoi(resp.Header.Get("ETag"))
+				if err != nil || body != strings.Repeat(strconv.Itoa(v%10), 1000*v) {
+					t.Errorf("ETag %q does not match a %d byte body", resp.Header.Get("ETag"), len(body))
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	e, body, ok := tr.load(tr.key(req))
+	if !ok {
+		t.Fatal("nothing cached after concurrent fetches")
+	}
+	v, err := strconv.Atoi(e.ETag)
+	if err != nil || string(body) != strings.Repeat(strconv.Itoa(v%10), 1000*v) {
+		t.Errorf("stored ETag %q does not match the stored %d byte body", e.ETag, len(body))
+	}
+}
+
+func TestTornEntryIsMiss(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "full body")
+	}))
+	defer srv.Close()
+	tr := New(t.TempDir(), nil)
+	tr.Rules = []Rule{{"", time.Hour}}
+	c := &http.Client{Transport: tr}
+	get(t, c, srv.URL)
+
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	key := tr.key(req)
+	if err := tr.writeFile(key+".body", []byte("full")); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := tr.load(key); ok {
+		t.Error("a body that does not match its metadata was served from the cache")
+	}
+}
+
+func TestStaleOnNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "cached motd")
+	}))
+	tr := New(t.TempDir(), nil)
+	c := &http.Client{Transport: tr}
+	resp, _ := get(t, c, srv.URL)
+	if resp.Header.Get("Warning") != "" {
+		t.Fatalf("fresh response has Warning %q", resp.Header.Get("Warning"))
+	}
+	srv.Close()
+	resp, body := get(t, c, srv.URL, "Cache-Control", "no-cache")
+	if body != "cached motd" || resp.Header.Get("Warning") != StaleWarning {
+		t.Errorf("offline: body %q, Warning %q, want the cached body marked stale", body, resp.Header.Get("Warning"))
+	}
+}
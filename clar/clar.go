@@ -0,0 +1,224 @@
+package clar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aesc-client/parse"
+	"github.com/PuerkitoBio/goquery"
+)
+
+func Fetch(client *http.Client, pageURL string) ([]parse.Clarification, error) {
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("GET %s returned %s", pageURL, resp.Status)
+	}
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return parse.ParseClarifications(body)
+}
+
+func Ask(client *http.Client, pageURL, problem, question string) error {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return errors.New("empty question")
+	}
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("GET %s returned %s", pageURL, resp.Status)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", pageURL, err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(parse.DecodeHTML(b, resp.Header.Get("Content-Type"))))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", pageURL, err)
+	}
+	form := doc.Find("form").FilterFunction(func(i int, s *goquery.Selection) bool {
+		return s.Find("textarea").Length() > 0
+	}).First()
+	if form.Length() == 0 {
+		return fmt.Errorf("no question form on %s", pageURL)
+	}
+	values := url.Values{}
+	form.Find("input").Each(func(i int, s *goquery.Selection) {
+		name := s.AttrOr("name", "")
+		typ := strings.ToLower(s.AttrOr("type", "text"))
+		if name == "" || typ == "submit" || typ == "button" || typ == "file" {
+			return
+		}
+		if (typ == "checkbox" || typ == "radio") && !s.Is("[checked]") {
+			return
+		}
+		values.Set(name, s.AttrOr("value", ""))
+		if problem != "" && typ == "text" && matchesAny(name, "problem", "subject", "task") {
+			values.Set(name, problem)
+		}
+	})
+	var selectErr error
+	form.Find("select").Each(func(i int, s *goquery.Selection) {
+		name := s.AttrOr("name", "")
+		if name == "" {
+			return
+		}
+		opt := s.Find("option[selected]").First()
+		if opt.Length() == 0 {
+			opt = s.Find("option").First()
+		}
+		if problem != "" {
+			match := s.Find("option").FilterFunction(func(i int, o *goquery.Selection) bool {
+				return optionMatches(o, problem)
+			}).First()
+			if match.Length() > 0 {
+				opt = match
+			} else if matchesAny(name, "problem", "subject", "task") {
+				selectErr = fmt.Errorf("problem %q is not offered by the question form", problem)
+			}
+		}
+		values.Set(name, opt.AttrOr("value", strings.TrimSpace(opt.Text())))
+	})
+	if selectErr != nil {
+		return selectErr
+	}
+	form.Find("textarea").First().Each(func(i int, s *goquery.Selection) {
+		values.Set(s.AttrOr("name", "question"), question)
+	})
+	action := resolve(pageURL, form.AttrOr("action", pageURL))
+	var body bytes.Buffer
+	contentType := "application/x-www-form-urlencoded"
+	if strings.Contains(strings.ToLower(form.AttrOr("enctype", "")), "multipart") {
+		w := multipart.NewWriter(&body)
+		for k, vs := range values {
+			for _, v := range vs {
+				_ = w.WriteField(k, v)
+			}
+		}
+		err := w.Close()
+		if err != nil {
+			return err
+		}
+		contentType = w.FormDataContentType()
+	} else {
+		body.WriteString(values.Encode())
+	}
+	req, err := http.NewRequest("POST", action, &body)
+	if err != nil {
+		return fmt.Errorf("create question request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Referer", pageURL)
+	resp2, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send question: %w", err)
+	}
+	defer resp2.Body.Close()
+	io.Copy(io.Discard, resp2.Body)
+	if resp2.StatusCode >= 400 {
+		return fmt.Errorf("send question failed: %s", resp2.Status)
+	}
+	return nil
+}
+
+func optionMatches(o *goquery.Selection, problem string) bool {
+	p := strings.ToLower(strings.TrimSpace(problem))
+	text := strings.ToLower(strings.TrimSpace(o.Text()))
+	val := strings.ToLower(o.AttrOr("value", ""))
+	if val == p || text == p {
+		return true
+	}
+	return strings.HasPrefix(text, p+".") || strings.HasPrefix(text, p+" ") || strings.HasPrefix(text, p+")")
+}
+
+func matchesAny(s string, keys ...string) bool {
+	s = strings.ToLower(s)
+	for _, k := range keys {
+		if strings.Contains(s, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func resolve(base, href string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	return b.ResolveReference(ref).String()
+}
+
+type Store struct {
+	Answered map[string]bool `json:"answered"`
+
+	path string
+}
+
+func LoadStore(path string) (*Store, error) {
+	s := &Store{Answered: map[string]bool{}, path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if s.Answered == nil {
+		s.Answered = map[string]bool{}
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	fdir := filepath.Dir(s.path)
+	err := os.MkdirAll(fdir, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", fdir, err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode clarifications: %w", err)
+	}
+	err = os.WriteFile(s.path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) NewAnswers(items []parse.Clarification) []parse.Clarification {
+	var out []parse.Clarification
+	for _, c := range items {
+		if c.Answered && !s.Answered[c.ID] {
+			s.Answered[c.ID] = true
+			out = append(out, c)
+		}
+	}
+	return out
+}
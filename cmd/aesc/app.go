@@ -0,0 +1,275 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"aesc-client/cache"
+	"aesc-client/login"
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/profile"
+)
+
+const (
+	loginPath = "/cs/login"
+	motdPath  = "/cs/motd"
+)
+
+var storeMu sync.Mutex
+
+type app struct {
+	profileName string
+	contestRef  string
+	noCache     bool
+	revalidate  bool
+	jsonOut     bool
+	jsonlOut    bool
+
+	cfg    *profile.Config
+	prof   *profile.Profile
+	client *http.Client
+}
+
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&a.profileName, "profile", a.profileName, "profile to use")
+	fs.StringVar(&a.contestRef, "contest", a.contestRef, "contest number or URL")
+	fs.BoolVar(&a.noCache, "no-cache", a.noCache, "bypass the local HTTP cache")
+	fs.BoolVar(&a.jsonOut, "json", a.jsonOut, "print JSON")
+	fs.BoolVar(&a.jsonlOut, "jsonl", a.jsonlOut, "print one JSON object per line")
+	return fs
+}
+
+func (a *app) out() *output.Printer {
+	switch {
+	case a.jsonlOut:
+		return output.New(os.Stdout, output.JSONL)
+	case a.jsonOut:
+		return output.New(os.Stdout, output.JSON)
+	}
+	return output.New(os.Stdout, output.Text)
+}
+
+func (a *app) config() (*profile.Config, error) {
+	if a.cfg != nil {
+		return a.cfg, nil
+	}
+	root, err := profile.DefaultRoot()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := profile.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	a.cfg = cfg
+	return cfg, nil
+}
+
+func (a *app) profile() (*profile.Profile, error) {
+	if a.prof != nil {
+		return a.prof, nil
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.Resolve(a.profileName)
+	if err != nil {
+		return nil, err
+	}
+	a.prof = p
+	return p, nil
+}
+
+func (a *app) session() (*http.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+	p, err := a.profile()
+	if err != nil {
+		return nil, err
+	}
+	client, err := login.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+	if !a.noCache {
+		tr := cache.New(p.Path("cache"), nil)
+		if a.revalidate {
+			tr.Rules = nil
+		}
+		client.Transport = tr
+	}
+	err = login.OpenSession(client, p.Server, loginPath, motdPath, p.Logpass, p.Cookies)
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+	return client, nil
+}
+
+func (a *app) get(path string) (*http.Response, error) {
+	return a.fetch(path, false)
+}
+
+func (a *app) getFresh(path string) (*http.Response, error) {
+	return a.fetch(path, true)
+}
+
+func (a *app) fetch(path string, fresh bool) (*http.Response, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, err
+	}
+	u := a.prof.URL(path)
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if fresh {
+		req.Header.Set("Cache-Control", "no-cache")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", u, err)
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s returned %s", u, resp.Status)
+	}
+	return resp, nil
+}
+
+func (a *app) contests() ([]parse.Contest, error) {
+	resp, err := a.get(motdPath)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return parse.ParseContests(body)
+}
+
+func (a *app) contestURL() (string, error) {
+	p, err := a.profile()
+	if err != nil {
+		return "", err
+	}
+	ref := a.contestRef
+	if ref == "" {
+		if v, _ := a.virtual(); v != nil && v.Active(time.Now()) {
+			ref = v.Contest
+		}
+	}
+	if ref == "" {
+		ref = p.Contest
+	}
+	if ref == "" {
+		return "", fmt.Errorf("no contest selected: pass --contest or run `aesc contests --use N`")
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return p.URL(ref), nil
+	}
+	contests, err := a.contests()
+	if err != nil {
+		return "", err
+	}
+	if n < 1 || n > len(contests) {
+		return "", fmt.Errorf("contest %d out of range 1..%d", n, len(contests))
+	}
+	return p.URL(contests[n-1].URL), nil
+}
+
+func (a *app) problems() ([]parse.Problem, error) {
+	u, err := a.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return parse.ParseProblems(body)
+}
+
+func (a *app) problem(ref string) (*parse.Problem, error) {
+	if strings.Contains(ref, "/") {
+		p, err := a.profile()
+		if err != nil {
+			return nil, err
+		}
+		u := p.URL(ref)
+		if problems, err := a.problems(); err == nil {
+			for i := range problems {
+				if p.URL(problems[i].URL) == u {
+					pr := problems[i]
+					pr.URL = u
+					return &pr, nil
+				}
+			}
+		}
+		return &parse.Problem{Name: ref, URL: u}, nil
+	}
+	problems, err := a.problems()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(ref)
+	if err == nil {
+		if n < 1 || n > len(problems) {
+			return nil, fmt.Errorf("problem %d out of range 1..%d", n, len(problems))
+		}
+		pr := problems[n-1]
+		pr.URL = a.prof.URL(pr.URL)
+		return &pr, nil
+	}
+	for i := range problems {
+		if strings.EqualFold(problems[i].Short, ref) {
+			pr := problems[i]
+			pr.URL = a.prof.URL(pr.URL)
+			return &pr, nil
+		}
+	}
+	for i := range problems {
+		if strings.HasPrefix(strings.ToLower(problems[i].Name), strings.ToLower(ref)) {
+			pr := problems[i]
+			pr.URL = a.prof.URL(pr.URL)
+			return &pr, nil
+		}
+	}
+	return nil, fmt.Errorf("no problem matching %q", ref)
+}
+
+func (a *app) message(event, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if o := a.out(); o.Structured() {
+		return o.Item(output.Message{OK: true, Event: event, Message: msg})
+	}
+	fmt.Println(msg)
+	return nil
+}
+
+func (a *app) progress(format string, args ...any) {
+	if a.out().Structured() {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+		return
+	}
+	fmt.Printf(format+"\n", args...)
+}
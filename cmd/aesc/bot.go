@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"aesc-client/bot"
+	"aesc-client/output"
+	"aesc-client/team"
+)
+
+func (a *app) attribute(changes []bot.Change) {
+	if !a.teamConfigured() {
+		return
+	}
+	b, err := a.board()
+	if err != nil {
+		return
+	}
+	members, err := b.Members()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "team: %v\n", err)
+		return
+	}
+	for i, c := range changes {
+		if c.Kind != bot.Solved {
+			continue
+		}
+		for _, m := range members {
+			if m.Last != nil && m.Last.Problem == c.Problem {
+				changes[i].Member = m.Name
+			}
+		}
+		if changes[i].Member == "" {
+			if names := team.Claimers(members, c.Problem); len(names) == 1 {
+				changes[i].Member = names[0]
+			}
+		}
+	}
+}
+
+func (a *app) botPoll(ctx context.Context, st *bot.State, hook *bot.Webhook, us string) error {
+	u, err := a.contestURL()
+	if err != nil {
+		return err
+	}
+	cur, err := a.standings()
+	if err != nil {
+		return err
+	}
+	changes := bot.Diff(st.Last[u], cur, us)
+	a.attribute(changes)
+	for _, c := range changes {
+		if o := a.out(); o.Structured() {
+			err = o.Event(output.NewEvent(c.Kind, c))
+			if err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), c.Text())
+		}
+	}
+	err = hook.Post(ctx, changes)
+	if err != nil {
+		return err
+	}
+	st.Last[u] = cur
+	return st.Save()
+}
+
+func cmdBot(a *app, args []string) error {
+	fs := a.flags("bot")
+	webhook := fs.String("webhook", "", "URL to POST standings changes to")
+	format := fs.String("format", "slack", "payload format: slack, telegram, matrix or json")
+	chatID := fs.String("chat-id", "", "chat_id for the telegram format")
+	name := fs.String("as", "", "our team name in the standings (default: the profile's team name)")
+	interval := fs.Duration("interval", time.Minute, "how often to check the standings")
+	once := fs.Bool("once", false, "check once against the last stored standings and exit")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *webhook == "" {
+		return errors.New("usage: aesc bot --webhook URL [--format slack|telegram|matrix|json] [--chat-id ID] [--as TEAM] [--interval 1m] [--once]")
+	}
+	err = bot.CheckFormat(*format)
+	if err != nil {
+		return err
+	}
+	if *format == "telegram" && *chatID == "" {
+		return errors.New("the telegram format needs --chat-id")
+	}
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	us := *name
+	if us == "" {
+		us = p.TeamName
+	}
+	if us == "" {
+		fmt.Fprintln(os.Stderr, "warning: our team name is unknown, only first solves will be posted (set it with `aesc team set --name TEAM`)")
+	}
+	hook := &bot.Webhook{URL: *webhook, Format: *format, ChatID: *chatID, Client: &http.Client{Timeout: 10 * time.Second}}
+	st, err := bot.LoadState(a.prof.Path("bot.json"))
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if !*once {
+		a.progress("posting standings changes to %s every %v, press Ctrl-C to stop", *webhook, *interval)
+	}
+	t := time.NewTicker(*interval)
+	defer t.Stop()
+	for {
+		err := a.botPoll(ctx, st, hook, us)
+		if err != nil && *once {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "bot: %v\n", err)
+		}
+		if *once {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"aesc-client/clar"
+	"aesc-client/notify"
+	"aesc-client/output"
+	"aesc-client/parse"
+)
+
+const clarsPath = "/cs/messages"
+
+func (a *app) newAnswers() ([]parse.Clarification, []parse.Clarification, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := clar.Fetch(client, a.prof.URL(clarsPath))
+	if err != nil {
+		return nil, nil, err
+	}
+	fresh, err := a.markAnswers(items)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, c := range fresh {
+		a.notify(notify.Event{Kind: notify.Clarification, Title: "jury answered about " + c.Problem, Body: "Q: " + c.Question + "\nA: " + c.Answer, Data: output.FromClarification(c)})
+	}
+	return items, fresh, nil
+}
+
+func (a *app) markAnswers(items []parse.Clarification) ([]parse.Clarification, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	st, err := clar.LoadStore(a.prof.Path("clars.json"))
+	if err != nil {
+		return nil, err
+	}
+	fresh := st.NewAnswers(items)
+	return fresh, st.Save()
+}
+
+func cmdClar(a *app, args []string) error {
+	if len(args) > 0 && args[0] == "ask" {
+		return cmdClarAsk(a, args[1:])
+	}
+	fs := a.flags("clar")
+	onlyNew := fs.Bool("new", false, "show only answers not seen before")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	items, fresh, err := a.newAnswers()
+	if err != nil {
+		return err
+	}
+	if *onlyNew {
+		items = fresh
+	}
+	if o := a.out(); o.Structured() {
+		return o.List(output.FromClarifications(items))
+	}
+	if len(items) == 0 {
+		fmt.Println("no clarifications")
+	}
+	for _, c := range items {
+		printClarification(c)
+	}
+	return nil
+}
+
+func cmdClarAsk(a *app, args []string) error {
+	fs := a.flags("clar ask")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: aesc clar ask <problem> <question>")
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	short := fs.Arg(0)
+	if pr, err := a.problem(short); err == nil && pr.Short != "" {
+		short = pr.Short
+	}
+	err = clar.Ask(client, a.prof.URL(clarsPath), short, strings.Join(fs.Args()[1:], " "))
+	if err != nil {
+		return err
+	}
+	return a.message("question_sent", "question sent")
+}
+
+func printClarification(c parse.Clarification) {
+	ts := ""
+	if !c.Time.IsZero() {
+		ts = c.Time.Format("15:04") + " "
+	}
+	fmt.Printf("%s[%s] Q: %s\n", ts, c.Problem, c.Question)
+	if c.Answered {
+		fmt.Printf("    A: %s\n", c.Answer)
+	} else {
+		fmt.Println("    (no answer yet)")
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"aesc-client/login"
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/submit"
+)
+
+func cmdLogin(a *app, args []string) error {
+	fs := a.flags("login")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	name, pass, err := login.ReadLogpass(p.Logpass)
+	if err != nil {
+		return err
+	}
+	client, err := login.NewClient()
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+	status, err := login.TryLogin(client, p.Server, loginPath, name, pass)
+	if err != nil {
+		return err
+	}
+	err = login.SaveCookies(client.Jar, p.Server, p.Cookies)
+	if err != nil {
+		return err
+	}
+	a.client = client
+	return a.message("login", "%s: login status: %s", p.Name, status)
+}
+
+func cmdContests(a *app, args []string) error {
+	fs := a.flags("contests")
+	use := fs.Int("use", 0, "make contest N the default for this profile")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	contests, err := a.contests()
+	if err != nil {
+		return err
+	}
+	if *use != 0 {
+		if *use < 1 || *use > len(contests) {
+			return fmt.Errorf("contest %d out of range 1..%d", *use, len(contests))
+		}
+		p, err := a.cfg.Get(a.prof.Name)
+		if err != nil {
+			return fmt.Errorf("%w: create one with `aesc profile add`", err)
+		}
+		p.Contest = contests[*use-1].URL
+		err = a.cfg.Save()
+		if err != nil {
+			return err
+		}
+		return a.message("contest", "using contest %s", contests[*use-1].Name)
+	}
+	if o := a.out(); o.Structured() {
+		for i := range contests {
+			contests[i].URL = a.prof.URL(contests[i].URL)
+		}
+		return o.List(output.FromContests(contests))
+	}
+	for i := range contests {
+		fmt.Printf("%d. %s -> %s\n", i+1, contests[i].Name, contests[i].URL)
+	}
+	return nil
+}
+
+func cmdProblems(a *app, args []string) error {
+	fs := a.flags("problems")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	problems, err := a.problems()
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		for i := range problems {
+			problems[i].URL = a.prof.URL(problems[i].URL)
+		}
+		return o.List(output.FromProblems(problems))
+	}
+	for i := range problems {
+		fmt.Printf("%d. %s -> %s%s\n", i+1, problems[i].Name, problems[i].URL, progress(&problems[i]))
+	}
+	return nil
+}
+
+func progress(p *parse.Problem) string {
+	switch {
+	case p.MaxScore > 0 && (p.Score > 0 || p.Attempts > 0):
+		return fmt.Sprintf(" [%d/%d]", p.Score, p.MaxScore)
+	case p.Solved:
+		return fmt.Sprintf(" [solved, %d attempts]", p.Attempts)
+	case p.Attempts > 0:
+		return fmt.Sprintf(" [%d attempts]", p.Attempts)
+	}
+	return ""
+}
+
+func cmdInfo(a *app, args []string) error {
+	fs := a.flags("info")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: aesc info <problem>")
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	pr, err := a.problem(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	text, err := parse.FetchStatementToString(client, pr.URL)
+	if err != nil {
+		return err
+	}
+	parse.FillFromStatement(pr, text)
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromProblem(*pr))
+	}
+	in, out := pr.Input, pr.Output
+	if in == "" {
+		in = "stdin"
+	}
+	if out == "" {
+		out = "stdout"
+	}
+	fmt.Printf("problem: %s (%s)\n", pr.Name, pr.Short)
+	fmt.Printf("url: %s\n", pr.URL)
+	if pr.TimeLimit > 0 {
+		fmt.Printf("time limit: %v\n", pr.TimeLimit)
+	}
+	switch {
+	case pr.MemoryLimit >= 1<<20:
+		fmt.Printf("memory limit: %d MB\n", pr.MemoryLimit>>20)
+	case pr.MemoryLimit > 0:
+		fmt.Printf("memory limit: %d KB\n", pr.MemoryLimit>>10)
+	}
+	fmt.Printf("input: %s\noutput: %s\n", in, out)
+	if pr.MaxScore > 0 {
+		fmt.Printf("max score: %d\n", pr.MaxScore)
+	}
+	return nil
+}
+
+func cmdStatement(a *app, args []string) error {
+	fs := a.flags("statement")
+	format := fs.String("format", "text", "output format: text, md or html")
+	save := fs.String("save", "", "save the statement and its attachments to DIR")
+	diff := fs.Bool("diff", false, "show what changed since the last fetch")
+	watchEvery := fs.Duration("watch", 0, "poll the statement at this interval and print changes")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: aesc statement [--format text|md|html] [--save DIR] [--diff] [--watch D] <problem>")
+	}
+	if *diff || *watchEvery > 0 {
+		a.revalidate = true
+	}
+	if *watchEvery > 0 {
+		return a.watchStatements(fs.Arg(0), *watchEvery)
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	pr, err := a.problem(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	st, err := parse.FetchStatement(client, pr.URL)
+	if err != nil {
+		return err
+	}
+	_, _, seen, err := a.history().Previous(pr.URL)
+	if err != nil {
+		return err
+	}
+	changes, err := a.recordStatement(pr, st.Text)
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() && *diff {
+		res := output.FromStatement(*pr, st, "text", st.Text)
+		res.Diff = changes
+		return o.Item(res)
+	}
+	if *diff {
+		switch {
+		case !seen:
+			fmt.Printf("%s: no earlier version recorded, saved the current one\n", pr.Name)
+		case changes != "":
+			fmt.Print(changes)
+		default:
+			fmt.Printf("%s: no changes since the last fetch\n", pr.Name)
+		}
+		return nil
+	}
+	name := pr.Short
+	if name == "" {
+		name = "statement"
+	}
+	if st.PDF != nil {
+		dir := *save
+		if dir == "" {
+			dir = a.prof.Path("statements")
+		}
+		path, err := savePDF(st, dir, name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "PDF statement saved to %s\n", path)
+	}
+	if *save != "" {
+		err := st.SaveAttachments(client, filepath.Join(*save, name+"_files"), name+"_files")
+		if err != nil {
+			return err
+		}
+	}
+	out, ext, err := statementText(st, *format)
+	if err != nil {
+		return err
+	}
+	if *save != "" {
+		path := filepath.Join(*save, name+ext)
+		err = os.WriteFile(path, []byte(out+"\n"), 0o644)
+		if err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		out = path
+	}
+	if o := a.out(); o.Structured() {
+		res := output.FromStatement(*pr, st, *format, out)
+		if *save != "" {
+			res.Text = ""
+		}
+		return o.Item(res)
+	}
+	fmt.Println(out)
+	printAttachments(st)
+	return nil
+}
+
+func savePDF(st *parse.Statement, dir, name string) (string, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, name+".pdf")
+	err = os.WriteFile(path, st.PDF, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func statementText(st *parse.Statement, format string) (string, string, error) {
+	switch format {
+	case "text":
+		return st.Text, ".txt", nil
+	case "md", "markdown":
+		out, err := st.Markdown()
+		return out, ".md", err
+	case "html":
+		out, err := st.HTML()
+		return out, ".html", err
+	}
+	return "", "", fmt.Errorf("unknown format %q", format)
+}
+
+func printAttachments(st *parse.Statement) {
+	if len(st.Attachments) == 0 {
+		return
+	}
+	fmt.Println("\nattachments:")
+	for _, at := range st.Attachments {
+		loc := at.URL
+		if at.Path != "" {
+			loc = at.Path
+		}
+		fmt.Printf("  %s (%s): %s\n", at.Name, at.Kind, loc)
+	}
+}
+
+func forceHint(err error) error {
+	if errors.Is(err, submit.ErrNotConfirmed) {
+		return fmt.Errorf("%w (pass --force to skip this check)", err)
+	}
+	return err
+}
+
+func cmdSubmit(a *app, args []string) error {
+	fs := a.flags("submit")
+	wait := fs.Bool("wait", false, "wait for the verdict")
+	lang := fs.String("lang", "", "compiler id to submit with (detected from the file name by default)")
+	name := fs.String("name", "", "file name to send, required to detect the language of stdin")
+	force := fs.Bool("force", false, "submit without asking about duplicates or a mismatched problem letter")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: aesc submit [--wait] [--force] [--lang L] [--name F] <problem> <file|->")
+	}
+	src, err := readSource(fs.Arg(1), *name, *lang)
+	if err != nil {
+		return err
+	}
+	pr, err := a.problem(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	c, err := a.check(pr, src, *force, askTTY, warnStderr)
+	if err != nil {
+		return err
+	}
+	if *wait {
+		s, err := a.submitAndWait(context.Background(), pr, src, c)
+		if err != nil {
+			return forceHint(err)
+		}
+		if o := a.out(); o.Structured() {
+			return o.Item(output.FromSubmission(pr.Short, *s))
+		}
+		printVerdict(s)
+		return nil
+	}
+	_, err = a.send(pr, src, c)
+	if err != nil {
+		return forceHint(err)
+	}
+	return a.message("submitted", "%s: solution submitted", pr.Name)
+}
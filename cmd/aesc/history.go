@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"aesc-client/history"
+	"aesc-client/output"
+	"aesc-client/parse"
+)
+
+func (a *app) history() *history.Store {
+	return history.New(a.prof.Path("statements", "history"))
+}
+
+func (a *app) watchStatements(ref string, every time.Duration) error {
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	var problems []parse.Problem
+	if ref == "" {
+		problems, err = a.problems()
+		if err != nil {
+			return err
+		}
+		for i := range problems {
+			problems[i].URL = a.prof.URL(problems[i].URL)
+		}
+	} else {
+		pr, err := a.problem(ref)
+		if err != nil {
+			return err
+		}
+		problems = []parse.Problem{*pr}
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+	a.progress("watching %d statement(s) every %v, press Ctrl-C to stop", len(problems), every)
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		for _, r := range parse.FetchStatements(client, problems, 4, nil) {
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", r.Problem.Name, r.Err)
+				continue
+			}
+			changes, err := a.recordStatement(&r.Problem, r.Text)
+			if err != nil {
+				return err
+			}
+			if o := a.out(); o.Structured() && changes != "" {
+				err := o.Event(output.NewEvent("statement_changed", output.Statement{Problem: r.Problem.Short, URL: r.Problem.URL, Format: "text", Text: r.Text, Attachments: []output.Attachment{}, Diff: changes}))
+				if err != nil {
+					return err
+				}
+			} else if changes != "" {
+				fmt.Printf("%s: statement changed at %s\n%s\n", r.Problem.Name, time.Now().Format("15:04:05"), changes)
+			}
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-t.C:
+		}
+	}
+}
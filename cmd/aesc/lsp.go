@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"aesc-client/lsp"
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/workspace"
+)
+
+var reCompilerDiag = regexp.MustCompile(`(?m)^[^\n:]*?([^/\\\n:]+):(\d+):(?:(\d+):)?\s*(?:fatal\s+)?(error|warning):\s*(.*)$`)
+
+type lspServer struct {
+	base *app
+	conn *lsp.Conn
+
+	mu   sync.Mutex
+	docs map[string]string
+	apps map[string]*lspWorkspace
+}
+
+type lspWorkspace struct {
+	mu sync.Mutex
+	a  *app
+}
+
+type lspDocParams struct {
+	URI    string `json:"uri"`
+	Format string `json:"format,omitempty"`
+	Force  bool   `json:"force,omitempty"`
+}
+
+type lspProblem struct {
+	Problem output.Problem `json:"problem"`
+	File    string         `json:"file"`
+}
+
+func cmdLSP(a *app, args []string) error {
+	fs := a.flags("lsp")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if a.out().Structured() {
+		return errors.New("lsp always speaks JSON-RPC, drop --json/--jsonl")
+	}
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+	s := &lspServer{
+		base: a,
+		conn: lsp.NewConn(os.Stdin, stdout),
+		docs: map[string]string{},
+		apps: map[string]*lspWorkspace{},
+	}
+	return s.serve()
+}
+
+func (s *lspServer) serve() error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		m, err := s.conn.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var perr *lsp.Error
+		if errors.As(err, &perr) {
+			s.conn.Reply(nil, nil, perr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		switch m.Method {
+		case "exit":
+			return nil
+		case "initialize", "shutdown", "textDocument/didOpen", "textDocument/didChange", "textDocument/didSave", "textDocument/didClose":
+			res, err := s.handle(m.Method, m.Params)
+			if m.IsRequest() {
+				s.conn.Reply(m.ID, res, err)
+			}
+			continue
+		}
+		if !m.IsRequest() {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.handle(m.Method, m.Params)
+			s.conn.Reply(m.ID, res, err)
+		}()
+	}
+}
+
+func (s *lspServer) handle(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1,
+					"save":      map[string]bool{"includeText": true},
+				},
+				"executeCommandProvider": map[string]any{
+					"commands": []string{"aesc.problem", "aesc.statement", "aesc.runSamples", "aesc.submit"},
+				},
+			},
+			"serverInfo": map[string]string{"name": "aesc"},
+		}, nil
+	case "shutdown":
+		return nil, nil
+	case "textDocument/didOpen":
+		var p lsp.DidOpenParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.setDoc(p.TextDocument.URI, p.TextDocument.Text)
+		return nil, nil
+	case "textDocument/didChange":
+		var p lsp.DidChangeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		if n := len(p.ContentChanges); n > 0 {
+			s.setDoc(p.TextDocument.URI, p.ContentChanges[n-1].Text)
+		}
+		return nil, nil
+	case "textDocument/didSave":
+		var p lsp.DidSaveParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		if p.Text != nil {
+			s.setDoc(p.TextDocument.URI, *p.Text)
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var p lsp.DidCloseParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		s.mu.Lock()
+		delete(s.docs, p.TextDocument.URI)
+		s.mu.Unlock()
+		return nil, nil
+	case "workspace/executeCommand":
+		var p lsp.ExecuteCommandParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, invalidParams(err)
+		}
+		var doc lspDocParams
+		if len(p.Arguments) > 0 {
+			if json.Unmarshal(p.Arguments[0], &doc.URI) != nil {
+				if err := json.Unmarshal(p.Arguments[0], &doc); err != nil {
+					return nil, invalidParams(err)
+				}
+			}
+		}
+		return s.command(p.Command, doc)
+	case "aesc/problem", "aesc/statement", "aesc/runSamples", "aesc/submit":
+		var doc lspDocParams
+		if err := json.Unmarshal(params, &doc); err != nil {
+			return nil, invalidParams(err)
+		}
+		return s.command("aesc."+method[len("aesc/"):], doc)
+	}
+	return nil, &lsp.Error{Code: lsp.MethodNotFound, Message: "unknown method " + method}
+}
+
+func invalidParams(err error) error {
+	return &lsp.Error{Code: lsp.InvalidParams, Message: err.Error()}
+}
+
+func (s *lspServer) command(name string, doc lspDocParams) (any, error) {
+	if doc.URI == "" {
+		return nil, &lsp.Error{Code: lsp.InvalidParams, Message: "missing document uri"}
+	}
+	a, pr, err := s.resolve(doc.URI)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "aesc.problem":
+		return lspProblem{Problem: output.FromProblem(*pr), File: lsp.URIToPath(doc.URI)}, nil
+	case "aesc.statement":
+		return s.statement(a, pr, doc.Format)
+	case "aesc.runSamples":
+		return s.runSamples(a, pr, doc.URI)
+	case "aesc.submit":
+		return s.submit(a, pr, doc.URI, doc.Force)
+	}
+	return nil, &lsp.Error{Code: lsp.MethodNotFound, Message: "unknown command " + name}
+}
+
+func (s *lspServer) setDoc(uri, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[uri] = text
+}
+
+func (s *lspServer) source(uri string) ([]byte, error) {
+	s.mu.Lock()
+	text, ok := s.docs[uri]
+	s.mu.Unlock()
+	if ok {
+		return []byte(text), nil
+	}
+	path := lsp.URIToPath(uri)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return b, nil
+}
+
+func (s *lspServer) app(m *workspace.Manifest) (*app, error) {
+	key := ""
+	if m != nil {
+		key = m.Dir()
+	}
+	s.mu.Lock()
+	w, ok := s.apps[key]
+	if !ok {
+		w = &lspWorkspace{}
+		s.apps[key] = w
+	}
+	s.mu.Unlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.a != nil {
+		return w.a, nil
+	}
+	a := &app{profileName: s.base.profileName, contestRef: s.base.contestRef, noCache: s.base.noCache}
+	if m != nil {
+		if m.Profile != "" {
+			a.profileName = m.Profile
+		}
+		if m.Contest != "" {
+			a.contestRef = m.Contest
+		}
+	}
+	_, err := a.session()
+	if err != nil {
+		return nil, err
+	}
+	w.a = a
+	return a, nil
+}
+
+func (s *lspServer) resolve(uri string) (*app, *parse.Problem, error) {
+	path := lsp.URIToPath(uri)
+	m, err := workspace.Find(filepath.Dir(path))
+	if errors.Is(err, os.ErrNotExist) {
+		m = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	a, err := s.app(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m != nil {
+		if ref, ok := m.Ref(path); ok {
+			pr, err := a.problem(ref)
+			return a, pr, err
+		}
+	}
+	problems, err := a.problems()
+	if err != nil {
+		return nil, nil, err
+	}
+	pr, ok := workspace.Guess(path, problems)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not mapped to a problem: add it to %s or name it after the problem letter", filepath.Base(path), workspace.FileName)
+	}
+	pr.URL = a.prof.URL(pr.URL)
+	return a, pr, nil
+}
+
+func (s *lspServer) statement(a *app, pr *parse.Problem, format string) (any, error) {
+	if format == "" {
+		format = "md"
+	}
+	st, err := parse.FetchStatement(a.client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	_, err = a.recordStatement(pr, st.Text)
+	if err != nil {
+		return nil, err
+	}
+	out, _, err := statementText(st, format)
+	if err != nil {
+		return nil, invalidParams(err)
+	}
+	return output.FromStatement(*pr, st, format, out), nil
+}
+
+func (s *lspServer) runSamples(a *app, pr *parse.Problem, uri string) (any, error) {
+	src, err := s.source(uri)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := fetchSamples(a.client, pr)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%s: no samples found in the statement", pr.Name)
+	}
+	file, cleanup, err := tempSource(lsp.URIToPath(uri), src)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	results, err := runSamples(ctx, pr, samples, file)
+	if err != nil {
+		s.publish(uri, compilerDiagnostics(string(src), filepath.Base(file), err.Error()))
+		return nil, err
+	}
+	var diags []lsp.Diagnostic
+	for _, r := range results {
+		if r.Passed {
+			continue
+		}
+		msg := fmt.Sprintf("sample %d: wrong answer\nexpected:\n%sgot:\n%s", r.Sample, r.Want, r.Got)
+		if r.Err != nil {
+			msg = fmt.Sprintf("sample %d: %v", r.Sample, r.Err)
+		}
+		diags = append(diags, lsp.Diagnostic{Range: lsp.LineRange(string(src), 0), Severity: lsp.SeverityWarning, Source: "aesc", Message: msg})
+	}
+	s.publish(uri, diags)
+	return output.FromSampleResults(results), nil
+}
+
+func (s *lspServer) submit(a *app, pr *parse.Problem, uri string, force bool) (any, error) {
+	src, err := s.source(uri)
+	if err != nil {
+		return nil, err
+	}
+	source := &source{name: filepath.Base(lsp.URIToPath(uri)), body: src}
+	c, err := a.check(pr, source, force, nil, func(w string) {
+		s.conn.Notify("window/showMessage", lsp.ShowMessageParams{Type: 2, Message: w})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sub, err := a.submitAndWait(context.Background(), pr, source, c)
+	if err != nil {
+		return nil, err
+	}
+	d := lsp.Diagnostic{Range: lsp.LineRange(string(src), 0), Severity: lsp.SeverityError, Source: "aesc", Message: verdictLine(sub)}
+	if sub.Accepted() {
+		d.Severity = lsp.SeverityInformation
+	}
+	s.publish(uri, []lsp.Diagnostic{d})
+	s.conn.Notify("window/showMessage", lsp.ShowMessageParams{Type: 3, Message: pr.Name + ": " + verdictLine(sub)})
+	return output.FromSubmission(pr.Short, *sub), nil
+}
+
+func tempSource(name string, src []byte) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "aesc-src")
+	if err != nil {
+		return "", nil, fmt.Errorf("temp dir: %w", err)
+	}
+	file := filepath.Join(dir, filepath.Base(name))
+	err = os.WriteFile(file, src, 0o600)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("write %s: %w", file, err)
+	}
+	return file, func() { os.RemoveAll(dir) }, nil
+}
+
+func (s *lspServer) publish(uri string, diags []lsp.Diagnostic) {
+	if diags == nil {
+		diags = []lsp.Diagnostic{}
+	}
+	s.conn.Notify("textDocument/publishDiagnostics", lsp.PublishDiagnosticsParams{URI: uri, Diagnostics: diags})
+}
+
+func compilerDiagnostics(src, name, out string) []lsp.Diagnostic {
+	var diags []lsp.Diagnostic
+	for _, m := range reCompilerDiag.FindAllStringSubmatch(out, -1) {
+		if m[1] != name {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		col, _ := strconv.Atoi(m[3])
+		sev := lsp.SeverityError
+		if m[4] == "warning" {
+			sev = lsp.SeverityWarning
+		}
+		r := lsp.LineRange(src, line-1)
+		if col > 0 {
+			r.Start.Character = col - 1
+		}
+		diags = append(diags, lsp.Diagnostic{Range: r, Severity: sev, Source: "aesc", Message: m[5]})
+	}
+	if len(diags) == 0 {
+		diags = append(diags, lsp.Diagnostic{Range: lsp.LineRange(src, 0), Severity: lsp.SeverityError, Source: "aesc", Message: out})
+	}
+	return diags
+}
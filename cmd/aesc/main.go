@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(a *app, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"login", "login", cmdLogin},
+		{"profile", "profile add|list|use ...", cmdProfile},
+		{"contests", "contests [--use N]", cmdContests},
+		{"status", "status [--once] [--warn 10m]", cmdStatus},
+		{"news", "news [--all]", cmdNews},
+		{"clar", "clar [--new] | clar ask <problem> <question>", cmdClar},
+		{"problems", "problems", cmdProblems},
+		{"info", "info <problem>", cmdInfo},
+		{"statement", "statement [--format text|md|html] [--save DIR] [--diff] [--watch D] <problem>", cmdStatement},
+		{"statements", "statements [--workers N] [--out DIR] [--watch D]", cmdStatements},
+		{"standings", "standings [--top N] [--record 1m] | standings --history [--as TEAM] [--svg FILE]", cmdStandings},
+		{"bot", "bot --webhook URL [--format slack|telegram|matrix|json] [--chat-id ID] [--interval 1m] [--once]", cmdBot},
+		{"virtual", "virtual <contest> [--duration 5h] | virtual status | virtual finish | virtual result", cmdVirtual},
+		{"stats", "stats [--offline]", cmdStats},
+		{"claim", "claim [--release] <problem>...", cmdClaim},
+		{"team", "team [status] | team set [--member NAME] [--sync DIR|URL] [--name TEAM] | team serve [--addr A] [--dir D]", cmdTeam},
+		{"notify", "notify [add|remove <bell|desktop[:CMD]|webhook:URL|command:CMD|none>] | notify test", cmdNotify},
+		{"serve", "serve [--addr 127.0.0.1:8765]", cmdServe},
+		{"lsp", "lsp (JSON-RPC over stdio for editors)", cmdLSP},
+		{"workspace", "workspace init [--ext cpp] [dir] | workspace map <file> <problem>", cmdWorkspace},
+		{"test", "test <problem> <file>", cmdTest},
+		{"submit", "submit [--wait] <problem> <file>", cmdSubmit},
+		{"watch", "watch [--debounce D] [--confirm] <problem> <file>", cmdWatch},
+		{"tui", "tui", cmdTUI},
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aesc [--profile NAME] [--contest N|URL] [--no-cache] [--json|--jsonl] <command> [args]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", c.usage)
+	}
+}
+
+func main() {
+	a := &app{}
+	fs := a.flags("aesc")
+	fs.Usage = usage
+	err := fs.Parse(os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	name := fs.Arg(0)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(a, fs.Args()[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"aesc-client/news"
+	"aesc-client/output"
+	"aesc-client/parse"
+)
+
+func (a *app) fetchNews() (*news.Store, []parse.Announcement, error) {
+	resp, err := a.get(motdPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := parse.ParseMotd(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse motd: %w", err)
+	}
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	st, err := news.Load(a.prof.Path("news.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return st, st.Merge(items), nil
+}
+
+func cmdNews(a *app, args []string) error {
+	fs := a.flags("news")
+	all := fs.Bool("all", false, "show every stored announcement, not only unseen ones")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	st, unseen, err := a.fetchNews()
+	if err != nil {
+		return err
+	}
+	show := unseen
+	if *all {
+		show = st.Items
+	}
+	if o := a.out(); o.Structured() {
+		err = o.List(output.FromAnnouncements(show))
+		if err != nil {
+			return err
+		}
+	} else {
+		if len(show) == 0 {
+			fmt.Println("no new announcements")
+		}
+		for _, it := range show {
+			printAnnouncement(it)
+		}
+	}
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	st.MarkSeen(unseen)
+	return st.Save()
+}
+
+func printAnnouncement(it parse.Announcement) {
+	if !it.Time.IsZero() {
+		fmt.Printf("[%s] ", it.Time.Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("%s\n\n", it.Text)
+}
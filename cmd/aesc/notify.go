@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"aesc-client/notify"
+	"aesc-client/parse"
+)
+
+func (a *app) notifier() (notify.Multi, error) {
+	p, err := a.profile()
+	if err != nil {
+		return nil, err
+	}
+	specs := p.Notify
+	if len(specs) == 0 {
+		specs = []string{"bell"}
+	}
+	return notify.New(specs)
+}
+
+func (a *app) notify(ev notify.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	n, err := a.notifier()
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err = n.Notify(ctx, ev)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "notify: %v\n", err)
+	}
+}
+
+func (a *app) recordStatement(pr *parse.Problem, text string) (string, error) {
+	storeMu.Lock()
+	changes, err := a.history().Record(pr.URL, pr.Name, text)
+	storeMu.Unlock()
+	if err != nil || changes == "" {
+		return changes, err
+	}
+	a.notify(notify.Event{Kind: notify.StatementChanged, Title: pr.Name + ": statement changed", Body: changes})
+	return changes, nil
+}
+
+func cmdNotify(a *app, args []string) error {
+	fs := a.flags("notify")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	rest := fs.Args()
+	if len(rest) == 0 {
+		specs := p.Notify
+		if len(specs) == 0 {
+			specs = []string{"bell (default)"}
+		}
+		if o := a.out(); o.Structured() {
+			return o.List(specs)
+		}
+		for _, s := range specs {
+			fmt.Println(s)
+		}
+		return nil
+	}
+	switch rest[0] {
+	case "test":
+		n, err := a.notifier()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		err = n.Notify(ctx, notify.Event{Kind: "test", Title: "aesc: test notification", Body: "notifications work", Time: time.Now()})
+		if err != nil {
+			return err
+		}
+		return a.message("notify", "sent a test notification to %d backend(s)", len(n))
+	case "add", "remove":
+		if len(rest) != 2 {
+			return errors.New("usage: aesc notify add|remove <bell|desktop[:CMD]|webhook:URL|command:CMD|none>")
+		}
+	default:
+		return fmt.Errorf("unknown notify command %q", rest[0])
+	}
+	stored, err := cfg.Get(p.Name)
+	if err != nil {
+		return fmt.Errorf("%w: create one with `aesc profile add`", err)
+	}
+	spec := rest[1]
+	if rest[0] == "add" {
+		if spec != "none" {
+			if _, err := notify.Parse(spec); err != nil {
+				return err
+			}
+		}
+		if !slices.Contains(stored.Notify, spec) {
+			stored.Notify = append(stored.Notify, spec)
+		}
+	} else {
+		i := slices.Index(stored.Notify, spec)
+		if i < 0 {
+			return fmt.Errorf("%s is not configured", spec)
+		}
+		stored.Notify = slices.Delete(stored.Notify, i, i+1)
+	}
+	err = cfg.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("notify", "%s: notifying via %v", p.Name, stored.Notify)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"aesc-client/login"
+	"aesc-client/output"
+)
+
+func cmdProfile(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: aesc profile add|list|use ...")
+	}
+	switch args[0] {
+	case "add":
+		return cmdProfileAdd(a, args[1:])
+	case "list":
+		return cmdProfileList(a, args[1:])
+	case "use":
+		return cmdProfileUse(a, args[1:])
+	}
+	return fmt.Errorf("unknown profile command %q", args[0])
+}
+
+func cmdProfileAdd(a *app, args []string) error {
+	fs := a.flags("profile add")
+	server := fs.String("server", "", "server base URL")
+	logpass := fs.String("logpass", "", "existing login/password file (read from stdin otherwise)")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: aesc profile add [--server URL] [--logpass FILE] <name>")
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	p, err := cfg.Add(fs.Arg(0), *server, *logpass)
+	if err != nil {
+		return err
+	}
+	if *logpass == "" {
+		fmt.Fprintln(os.Stderr, "enter login and password on two lines:")
+		s := bufio.NewScanner(os.Stdin)
+		var lines []string
+		for len(lines) < 2 && s.Scan() {
+			lines = append(lines, s.Text())
+		}
+		if len(lines) < 2 {
+			return errors.New("expected login and password")
+		}
+		err := login.WriteLogpass(p.Logpass, lines[0], lines[1])
+		if err != nil {
+			return err
+		}
+	}
+	err = cfg.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("profile_added", "added profile %s (%s)", p.Name, p.Server)
+}
+
+func cmdProfileList(a *app, args []string) error {
+	fs := a.flags("profile list")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		list := make([]output.Profile, 0, len(cfg.Profiles))
+		for _, p := range cfg.Profiles {
+			list = append(list, output.Profile{Name: p.Name, Server: p.Server, Current: p.Name == cfg.Current, Contest: p.Contest})
+		}
+		return o.List(list)
+	}
+	for _, p := range cfg.Profiles {
+		mark := " "
+		if p.Name == cfg.Current {
+			mark = "*"
+		}
+		fmt.Printf("%s %s -> %s\n", mark, p.Name, p.Server)
+	}
+	return nil
+}
+
+func cmdProfileUse(a *app, args []string) error {
+	fs := a.flags("profile use")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: aesc profile use <name>")
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	err = cfg.Use(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	err = cfg.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("profile", "using profile %s", fs.Arg(0))
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"aesc-client/clar"
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/submit"
+)
+
+const (
+	maxSourceSize = 1 << 20
+	apiHeader     = "X-Aesc"
+)
+
+type apiHandler func(a *app, r *http.Request) (any, error)
+
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+
+func badRequest(format string, args ...any) error {
+	return &apiError{http.StatusBadRequest, fmt.Errorf(format, args...)}
+}
+
+func cmdServe(a *app, args []string) error {
+	fs := a.flags("serve")
+	addr := fs.String("addr", "127.0.0.1:8765", "address to listen on (loopback only)")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	host, _, err := net.SplitHostPort(*addr)
+	if err != nil {
+		return fmt.Errorf("bad address %q: %w", *addr, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("refusing to listen on %s: the API shares your session, bind it to localhost", host)
+	}
+	_, err = a.session()
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	route := func(pattern string, h apiHandler) {
+		mux.Handle(pattern, a.api(h))
+	}
+	route("GET /api/contests", apiContests)
+	route("GET /api/problems", apiProblems)
+	route("GET /api/problems/{ref}", apiProblem)
+	route("GET /api/problems/{ref}/statement", apiStatement)
+	route("GET /api/problems/{ref}/submissions", apiSubmissions)
+	route("POST /api/problems/{ref}/submit", apiSubmit)
+	route("GET /api/status", apiStatus)
+	route("GET /api/standings", apiStandings)
+	route("GET /api/news", apiNews)
+	route("GET /api/clars", apiClars)
+	route("POST /api/clars/seen", apiClarsSeen)
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", *addr, err)
+	}
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	fmt.Fprintf(os.Stderr, "serving profile %s on http://%s/api/, press Ctrl-C to stop\n", a.prof.Name, ln.Addr())
+	err = srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (a *app) api(h apiHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !loopbackHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, output.Message{Message: "forbidden host " + r.Host})
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !loopbackOrigin(origin) {
+			writeJSON(w, http.StatusForbidden, output.Message{Message: "forbidden origin " + origin})
+			return
+		}
+		if r.Method != "GET" && r.Header.Get(apiHeader) != "1" {
+			writeJSON(w, http.StatusForbidden, output.Message{Message: "missing " + apiHeader + ": 1 header"})
+			return
+		}
+		req := *a
+		req.jsonOut, req.jsonlOut = false, false
+		if c := r.URL.Query().Get("contest"); c != "" {
+			req.contestRef = c
+		}
+		v, err := h(&req, r)
+		if err != nil {
+			status := http.StatusBadGateway
+			var ae *apiError
+			if errors.As(err, &ae) {
+				status = ae.status
+			}
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+			writeJSON(w, status, output.Message{Message: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	})
+}
+
+func loopbackHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+func loopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return loopbackHost(u.Host)
+}
+
+func queryBool(r *http.Request, key string) (bool, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest("bad %s=%q: use 1 or 0", key, v)
+	}
+	return b, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func (a *app) pathProblem(r *http.Request) (*parse.Problem, error) {
+	pr, err := a.problem(r.PathValue("ref"))
+	if err != nil {
+		return nil, &apiError{http.StatusNotFound, err}
+	}
+	return pr, nil
+}
+
+func apiContests(a *app, r *http.Request) (any, error) {
+	contests, err := a.contests()
+	if err != nil {
+		return nil, err
+	}
+	for i := range contests {
+		contests[i].URL = a.prof.URL(contests[i].URL)
+	}
+	return output.FromContests(contests), nil
+}
+
+func apiProblems(a *app, r *http.Request) (any, error) {
+	problems, err := a.problems()
+	if err != nil {
+		return nil, err
+	}
+	for i := range problems {
+		problems[i].URL = a.prof.URL(problems[i].URL)
+	}
+	return output.FromProblems(problems), nil
+}
+
+func apiProblem(a *app, r *http.Request) (any, error) {
+	pr, err := a.pathProblem(r)
+	if err != nil {
+		return nil, err
+	}
+	text, err := parse.FetchStatementToString(a.client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	parse.FillFromStatement(pr, text)
+	return output.FromProblem(*pr), nil
+}
+
+func apiStatement(a *app, r *http.Request) (any, error) {
+	pr, err := a.pathProblem(r)
+	if err != nil {
+		return nil, err
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+	record, err := queryBool(r, "record")
+	if err != nil {
+		return nil, err
+	}
+	st, err := parse.FetchStatement(a.client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	if record {
+		_, err = a.recordStatement(pr, st.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+	out, _, err := statementText(st, format)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return output.FromStatement(*pr, st, format, out), nil
+}
+
+func apiSubmissions(a *app, r *http.Request) (any, error) {
+	pr, err := a.pathProblem(r)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := submit.FetchSubmissions(a.client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	return output.FromSubmissions(pr.Short, subs), nil
+}
+
+func apiSubmit(a *app, r *http.Request) (any, error) {
+	pr, err := a.pathProblem(r)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(r.URL.Query().Get("name"))
+	if name == "." || name == "/" {
+		return nil, badRequest("missing ?name=<file name>")
+	}
+	src, err := io.ReadAll(io.LimitReader(r.Body, maxSourceSize+1))
+	if err != nil {
+		return nil, badRequest("read body: %v", err)
+	}
+	if len(src) > maxSourceSize {
+		return nil, badRequest("source is larger than %d bytes", maxSourceSize)
+	}
+	force, err := queryBool(r, "force")
+	if err != nil {
+		return nil, err
+	}
+	wait, err := queryBool(r, "wait")
+	if err != nil {
+		return nil, err
+	}
+	source := &source{name: name, lang: r.URL.Query().Get("lang"), body: src}
+	c, err := a.check(pr, source, force, nil, func(w string) {
+		log.Printf("%s: %s", pr.Name, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !wait {
+		_, err = a.send(pr, source, c)
+		if err != nil {
+			return nil, checkError(err)
+		}
+		return output.Message{OK: true, Event: "submitted", Message: pr.Name + ": solution submitted"}, nil
+	}
+	s, err := a.submitAndWait(r.Context(), pr, source, c)
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return output.FromSubmission(pr.Short, *s), nil
+}
+
+func checkError(err error) error {
+	switch {
+	case errors.Is(err, submit.ErrNotConfirmed):
+		return &apiError{http.StatusConflict, fmt.Errorf("%w (add force=1 to submit anyway)", err)}
+	case errors.Is(err, submit.ErrRefused):
+		return badRequest("%v", err)
+	}
+	return err
+}
+
+func apiStatus(a *app, r *http.Request) (any, error) {
+	c, err := a.contest()
+	if err != nil {
+		return nil, err
+	}
+	return output.ContestStatus(*c, c.Now()), nil
+}
+
+func apiStandings(a *app, r *http.Request) (any, error) {
+	st, err := a.standings()
+	if err != nil {
+		return nil, err
+	}
+	return output.FromStandings(st), nil
+}
+
+func apiNews(a *app, r *http.Request) (any, error) {
+	st, _, err := a.fetchNews()
+	if err != nil {
+		return nil, err
+	}
+	return output.FromAnnouncements(st.Items), nil
+}
+
+func apiClars(a *app, r *http.Request) (any, error) {
+	items, err := clar.Fetch(a.client, a.prof.URL(clarsPath))
+	if err != nil {
+		return nil, err
+	}
+	return output.FromClarifications(items), nil
+}
+
+func apiClarsSeen(a *app, r *http.Request) (any, error) {
+	_, fresh, err := a.newAnswers()
+	if err != nil {
+		return nil, err
+	}
+	return output.FromClarifications(fresh), nil
+}
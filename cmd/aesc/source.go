@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aesc-client/parse"
+	"aesc-client/submit"
+)
+
+type source struct {
+	name string
+	lang string
+	body []byte
+}
+
+func readSource(path, name, lang string) (*source, error) {
+	var body []byte
+	var err error
+	if path == "-" {
+		if name == "" && lang == "" {
+			return nil, errors.New("reading from stdin: pass --name or --lang so the language is known")
+		}
+		body, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		if name == "" {
+			name = "solution"
+		}
+	} else {
+		body, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		if name == "" {
+			name = filepath.Base(path)
+		}
+	}
+	return &source{name: name, lang: lang, body: body}, nil
+}
+
+func (a *app) submitLog() (*submit.Log, error) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	return submit.OpenLog(a.prof.Path("submissions.jsonl"))
+}
+
+func appendLog(l *submit.Log, e submit.Entry) error {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	return l.Append(e)
+}
+
+func (a *app) check(pr *parse.Problem, src *source, force bool, confirm func(string) bool, warn func(string)) (*submit.Checker, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, err
+	}
+	h, err := a.submitLog()
+	if err != nil {
+		return nil, err
+	}
+	if pr.Input == "" && pr.Output == "" && bytes.Contains(src.body, []byte("freopen")) {
+		if text, err := parse.FetchStatementToString(client, pr.URL); err == nil {
+			parse.FillFromStatement(pr, text)
+		}
+	}
+	return &submit.Checker{
+		Problem: pr.Short,
+		Key:     pr.URL,
+		FileIO:  pr.Input != "" || pr.Output != "",
+		History: h,
+		Force:   force,
+		Confirm: confirm,
+		Warn:    warn,
+	}, nil
+}
+
+func (a *app) send(pr *parse.Problem, src *source, c *submit.Checker) (*submit.Entry, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, err
+	}
+	l, err := a.submitLog()
+	if err != nil {
+		return nil, err
+	}
+	err = submit.Submit(client, pr.URL, bytes.NewReader(src.body), src.name, src.lang, c)
+	if err != nil {
+		return nil, err
+	}
+	contest, _ := a.contestURL()
+	e := submit.NewEntry(contest, pr, src.name, src.lang, src.body)
+	e.Member, _ = a.member()
+	if v, _ := a.virtual(); v != nil {
+		v.Tag(&e)
+	}
+	err = appendLog(l, e)
+	if err != nil {
+		return nil, err
+	}
+	a.recordActivity(&e)
+	return &e, nil
+}
+
+func (a *app) logVerdict(e *submit.Entry, s *parse.Submission) error {
+	l, err := a.submitLog()
+	if err != nil {
+		return err
+	}
+	e.SetVerdict(s)
+	err = appendLog(l, *e)
+	if err != nil {
+		return err
+	}
+	a.recordActivity(e)
+	return nil
+}
+
+func warnStderr(msg string) {
+	fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+}
+
+func askTTY(question string) bool {
+	return promptTTY(question + ". Submit anyway?")
+}
+
+func promptTTY(prompt string) bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+	fmt.Fprintf(tty, "%s [y/N] ", prompt)
+	ans, _ := bufio.NewReader(tty).ReadString('\n')
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(ans)), "y")
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/standings"
+)
+
+var errStandingsHidden = errors.New("standings are hidden during a virtual contest: finish it with `aesc virtual finish`")
+
+func (a *app) standings() (*parse.Standings, error) {
+	u, err := a.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	err = a.checkHidden(u)
+	if err != nil {
+		return nil, err
+	}
+	return a.fetchStandings(u)
+}
+
+func (a *app) checkHidden(contest string) error {
+	if v, _ := a.virtual(); v != nil && v.Active(time.Now()) && v.Contest == contest {
+		return errStandingsHidden
+	}
+	return nil
+}
+
+func (a *app) fetchStandings(u string) (*parse.Standings, error) {
+	resp, err := a.getFresh(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	st, err := parse.ParseStandings(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse standings: %w", err)
+	}
+	storeMu.Lock()
+	snaps, err := standings.Open(a.prof.Path("standings"), u)
+	if err == nil {
+		_, err = snaps.Record(st, time.Now())
+	}
+	storeMu.Unlock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not store standings snapshot: %v\n", err)
+	}
+	return st, nil
+}
+
+func (a *app) snapshots() (*standings.Store, error) {
+	u, err := a.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	err = a.checkHidden(u)
+	if err != nil {
+		return nil, err
+	}
+	return standings.Open(a.prof.Path("standings"), u)
+}
+
+func cmdStandings(a *app, args []string) error {
+	fs := a.flags("standings")
+	top := fs.Int("top", 0, "show only the first N rows")
+	history := fs.Bool("history", false, "chart our place and solved count over the stored snapshots")
+	name := fs.String("as", "", "team to chart (default: the profile's team name)")
+	svg := fs.String("svg", "", "write the history chart as SVG to this file")
+	chartWidth := fs.Int("width", 60, "history chart width in columns")
+	chartHeight := fs.Int("height", 8, "history chart height in rows")
+	record := fs.Duration("record", 0, "keep fetching the standings at this interval to store snapshots")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *history {
+		return a.standingsHistory(*name, *svg, *chartWidth, *chartHeight)
+	}
+	if *record > 0 {
+		return a.recordStandings(*record)
+	}
+	st, err := a.standings()
+	if err != nil {
+		return err
+	}
+	if *top > 0 && len(st.Rows) > *top {
+		st.Rows = st.Rows[:*top]
+	}
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromStandings(st))
+	}
+	if len(st.Rows) == 0 {
+		fmt.Println("no standings table found")
+		return nil
+	}
+	width := 4
+	for _, r := range st.Rows {
+		width = max(width, len([]rune(r.Team)))
+	}
+	fmt.Printf("%4s  %-*s", "#", width, "team")
+	for _, p := range st.Problems {
+		fmt.Printf(" %5s", p)
+	}
+	fmt.Printf(" %6s %7s\n", "solved", "penalty")
+	for _, r := range st.Rows {
+		fmt.Printf("%4d  %s%s", r.Place, r.Team, strings.Repeat(" ", width-len([]rune(r.Team))))
+		for _, p := range st.Problems {
+			fmt.Printf(" %5s", resultCell(r.Results[p]))
+		}
+		fmt.Printf(" %6d %7d\n", r.Solved, r.Penalty)
+	}
+	return nil
+}
+
+func resultCell(r parse.ProblemResult) string {
+	switch {
+	case r.Score > 0 && !r.Solved:
+		return fmt.Sprint(r.Score)
+	case r.Solved && r.Attempts > 1:
+		return fmt.Sprintf("+%d", r.Attempts-1)
+	case r.Solved:
+		return "+"
+	case r.Attempts > 0:
+		return fmt.Sprintf("-%d", r.Attempts)
+	}
+	return "."
+}
+
+func (a *app) standingsHistory(name, svg string, width, height int) error {
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = p.TeamName
+	}
+	if name == "" {
+		return errors.New("which team? pass --as TEAM or set it with `aesc team set --name TEAM`")
+	}
+	snaps, err := a.snapshots()
+	if err != nil {
+		return err
+	}
+	if len(snaps.Snapshots()) == 0 {
+		return errors.New("no standings snapshots stored yet: run `aesc standings --record 1m` during the contest")
+	}
+	series := snaps.Series(name)
+	if len(series.Points) == 0 {
+		return fmt.Errorf("%q is not in the stored standings (known: %s)", name, strings.Join(snaps.Teams(), ", "))
+	}
+	if svg != "" {
+		err = os.WriteFile(svg, []byte(series.SVG()), 0o644)
+		if err != nil {
+			return fmt.Errorf("write %s: %w", svg, err)
+		}
+		a.progress("wrote %s", svg)
+	}
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromSeries(series))
+	}
+	if svg == "" {
+		fmt.Print(series.ASCII(width, height))
+	}
+	return nil
+}
+
+func (a *app) recordStandings(every time.Duration) error {
+	snaps, err := a.snapshots()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	a.progress("recording standings every %v, press Ctrl-C to stop", every)
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		n := len(snaps.Snapshots())
+		_, err := a.standings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "standings: %v\n", err)
+		}
+		snaps, err = a.snapshots()
+		if err != nil {
+			return err
+		}
+		if len(snaps.Snapshots()) > n {
+			a.progress("%s: standings changed, %d snapshot(s) stored", time.Now().Format("15:04:05"), len(snaps.Snapshots()))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"aesc-client/output"
+	"aesc-client/parse"
+)
+
+func cmdStatements(a *app, args []string) error {
+	fs := a.flags("statements")
+	workers := fs.Int("workers", 4, "number of parallel downloads")
+	outDir := fs.String("out", "", "save each statement to DIR/<problem>.txt instead of printing")
+	watchEvery := fs.Duration("watch", 0, "poll all statements at this interval and print changes")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *watchEvery > 0 {
+		a.revalidate = true
+		return a.watchStatements("", *watchEvery)
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	problems, err := a.problems()
+	if err != nil {
+		return err
+	}
+	for i := range problems {
+		problems[i].URL = a.prof.URL(problems[i].URL)
+	}
+	results := parse.FetchStatements(client, problems, *workers, func(done, total int, r *parse.StatementResult) {
+		status := "ok"
+		if r.Err != nil {
+			status = "failed"
+		}
+		fmt.Fprintf(os.Stderr, "\r\033[K[%d/%d] %s %s", done, total, r.Problem.Short, status)
+	})
+	if len(results) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	failed := 0
+	list := []output.Statement{}
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %v\n", r.Problem.Name, r.Err)
+			continue
+		}
+		_, err := a.recordStatement(&r.Problem, r.Text)
+		if err != nil {
+			return err
+		}
+		if o := a.out(); o.Structured() {
+			list = append(list, output.Statement{Problem: r.Problem.Short, URL: r.Problem.URL, Format: "text", Text: r.Text, Attachments: []output.Attachment{}})
+			continue
+		}
+		if *outDir == "" {
+			fmt.Printf("==== %s ====\n%s\n\n", r.Problem.Name, r.Text)
+			continue
+		}
+		err = os.MkdirAll(*outDir, 0o755)
+		if err != nil {
+			return fmt.Errorf("mkdir %s: %w", *outDir, err)
+		}
+		path := filepath.Join(*outDir, r.Problem.Short+".txt")
+		err = os.WriteFile(path, []byte(r.Text+"\n"), 0o644)
+		if err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+	if o := a.out(); o.Structured() {
+		err := o.List(list)
+		if err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d statements failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/stats"
+	"aesc-client/submit"
+)
+
+func cmdStats(a *app, args []string) error {
+	fs := a.flags("stats")
+	offline := fs.Bool("offline", false, "do not fetch verdicts that are still unknown")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	_, err = a.profile()
+	if err != nil {
+		return err
+	}
+	l, err := a.submitLog()
+	if err != nil {
+		return err
+	}
+	if !*offline {
+		err = a.refreshVerdicts(l)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not refresh verdicts: %v\n", err)
+		}
+	}
+	entries := l.Entries()
+	if a.contestRef != "" {
+		u, err := a.contestURL()
+		if err != nil {
+			return err
+		}
+		var only []submit.Entry
+		for _, e := range entries {
+			if e.Contest == u {
+				only = append(only, e)
+			}
+		}
+		entries = only
+	}
+	s := stats.Compute(entries)
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromStats(s))
+	}
+	if s.Submissions == 0 {
+		fmt.Println("no submissions logged yet")
+		return nil
+	}
+	fmt.Printf("submissions: %d in %d contest(s), problems tried: %d, solved: %d\n", s.Submissions, s.Contests, s.Tried, s.Solved)
+	if s.Solved > 0 {
+		fmt.Printf("mean time to AC: %s\n", clock(s.MeanTimeToAC()))
+	}
+	fmt.Println("\nverdicts:")
+	verdicts := make([]string, 0, len(s.Verdicts))
+	for v := range s.Verdicts {
+		verdicts = append(verdicts, v)
+	}
+	sort.Slice(verdicts, func(i, j int) bool {
+		if s.Verdicts[verdicts[i]] != s.Verdicts[verdicts[j]] {
+			return s.Verdicts[verdicts[i]] > s.Verdicts[verdicts[j]]
+		}
+		return verdicts[i] < verdicts[j]
+	})
+	for _, v := range verdicts {
+		fmt.Printf("  %-30s %d\n", v, s.Verdicts[v])
+	}
+	contest := "\x00"
+	for _, p := range s.Problems {
+		if p.Contest != contest {
+			contest = p.Contest
+			name := contest
+			if name == "" {
+				name = "(unknown contest)"
+			}
+			fmt.Printf("\n%s\n", name)
+		}
+		name := p.Problem
+		if name == "" {
+			name = p.URL
+		}
+		if p.Solved {
+			fmt.Printf("  %-4s solved   %d attempt(s), AC on try %d after %s\n", name, p.Attempts, p.AttemptsToAC, clock(p.TimeToAC))
+		} else {
+			fmt.Printf("  %-4s unsolved %d attempt(s)\n", name, p.Attempts)
+		}
+	}
+	return nil
+}
+
+func (a *app) refreshVerdicts(l *submit.Log) error {
+	claimed := map[string]map[string]bool{}
+	byURL := map[string][]submit.Entry{}
+	for _, e := range l.Entries() {
+		if claimed[e.URL] == nil {
+			claimed[e.URL] = map[string]bool{}
+		}
+		if e.SubmissionID != "" {
+			claimed[e.URL][e.SubmissionID] = true
+		}
+		if !e.Final() && time.Since(e.Time) < 30*24*time.Hour {
+			byURL[e.URL] = append(byURL[e.URL], e)
+		}
+	}
+	if len(byURL) == 0 {
+		return nil
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	for u, entries := range byURL {
+		subs, err := submit.FetchSubmissions(client, u)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			s := findSubmission(subs, &e, claimed[u])
+			if s == nil || (s.Pending() && e.SubmissionID != "") {
+				continue
+			}
+			claimed[u][s.ID] = true
+			e.SetVerdict(s)
+			err := appendLog(l, e)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func findSubmission(subs []parse.Submission, e *submit.Entry, claimed map[string]bool) *parse.Submission {
+	var best *parse.Submission
+	for i := range subs {
+		if e.SubmissionID != "" {
+			if subs[i].ID == e.SubmissionID {
+				return &subs[i]
+			}
+			continue
+		}
+		if claimed[subs[i].ID] || subs[i].Time.IsZero() {
+			continue
+		}
+		d := subs[i].Time.Sub(e.Time).Abs()
+		if d <= 2*time.Minute && (best == nil || d < best.Time.Sub(e.Time).Abs()) {
+			best = &subs[i]
+		}
+	}
+	return best
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"aesc-client/notify"
+	"aesc-client/output"
+	"aesc-client/parse"
+)
+
+func (a *app) contest() (*parse.Contest, error) {
+	u, err := a.contestURL()
+	if err != nil {
+		return nil, err
+	}
+	c := &parse.Contest{URL: u}
+	contests, err := a.contests()
+	if err == nil {
+		for i := range contests {
+			if a.prof.URL(contests[i].URL) == u {
+				*c = contests[i]
+				c.URL = u
+			}
+		}
+	}
+	resp, err := a.getFresh(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+	if off, ok := parse.ServerOffsetFromHeader(resp.Header, received); ok {
+		c.ServerOffset = off
+	}
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	err = parse.ParseContestPage(body, c, received)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", u, err)
+	}
+	if c.Name == "" {
+		c.Name = u
+	}
+	return c, nil
+}
+
+func cmdStatus(a *app, args []string) error {
+	fs := a.flags("status")
+	once := fs.Bool("once", false, "print the status once and exit")
+	warn := fs.Duration("warn", 10*time.Minute, "warn this long before the freeze and the end")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	c, err := a.contest()
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		return a.statusEvents(o, c, *once, *warn)
+	}
+	fmt.Println(c.Name)
+	if !c.Start.IsZero() {
+		fmt.Printf("start: %s\n", c.Start.Format("2006-01-02 15:04:05"))
+	}
+	if !c.End.IsZero() {
+		fmt.Printf("end:   %s\n", c.End.Format("2006-01-02 15:04:05"))
+	}
+	if !c.Freeze.IsZero() {
+		fmt.Printf("freeze: %s\n", c.Freeze.Format("2006-01-02 15:04:05"))
+	}
+	if c.ServerOffset != 0 {
+		fmt.Printf("server clock offset: %v\n", c.ServerOffset)
+	}
+	if *once || c.End.IsZero() {
+		fmt.Println(statusLine(c, c.Now()))
+		return nil
+	}
+	warnedFreeze, warnedEnd := false, false
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		now := c.Now()
+		fmt.Printf("\r\033[K%s", statusLine(c, now))
+		if !warnedFreeze && !c.Freeze.IsZero() && now.Before(c.Freeze) && c.Freeze.Sub(now) <= *warn {
+			warnedFreeze = true
+			fmt.Fprintf(os.Stderr, "\nwarning: standings freeze in %s\n", clock(c.Freeze.Sub(now)))
+			a.notify(freezeEvent(c, now))
+		}
+		if !warnedEnd && now.Before(c.End) && c.End.Sub(now) <= *warn {
+			warnedEnd = true
+			fmt.Fprintf(os.Stderr, "\nwarning: contest ends in %s\n", clock(c.End.Sub(now)))
+			a.notify(endEvent(c, now))
+		}
+		if c.StateAt(now) == parse.StateFinished {
+			fmt.Println()
+			return nil
+		}
+		<-t.C
+	}
+}
+
+func (a *app) statusEvents(o *output.Printer, c *parse.Contest, once bool, warn time.Duration) error {
+	if once || c.End.IsZero() {
+		return o.Item(output.ContestStatus(*c, c.Now()))
+	}
+	warnedFreeze, warnedEnd := false, false
+	var last parse.ContestState
+	started := false
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	for {
+		now := c.Now()
+		st := c.StateAt(now)
+		if !started || st != last {
+			started, last = true, st
+			err := o.Event(output.NewEvent("state", output.ContestStatus(*c, now)))
+			if err != nil {
+				return err
+			}
+		}
+		if !warnedFreeze && !c.Freeze.IsZero() && now.Before(c.Freeze) && c.Freeze.Sub(now) <= warn {
+			warnedFreeze = true
+			err := o.Event(output.NewEvent("freeze_soon", output.ContestStatus(*c, now)))
+			if err != nil {
+				return err
+			}
+			a.notify(freezeEvent(c, now))
+		}
+		if !warnedEnd && now.Before(c.End) && c.End.Sub(now) <= warn {
+			warnedEnd = true
+			err := o.Event(output.NewEvent("end_soon", output.ContestStatus(*c, now)))
+			if err != nil {
+				return err
+			}
+			a.notify(endEvent(c, now))
+		}
+		if st == parse.StateFinished {
+			return nil
+		}
+		<-t.C
+	}
+}
+
+func freezeEvent(c *parse.Contest, now time.Time) notify.Event {
+	return notify.Event{Kind: notify.FreezeSoon, Title: c.Name + ": standings freeze in " + clock(c.Freeze.Sub(now)), Data: output.ContestStatus(*c, now)}
+}
+
+func endEvent(c *parse.Contest, now time.Time) notify.Event {
+	return notify.Event{Kind: notify.ContestEnding, Title: c.Name + ": contest ends in " + clock(c.End.Sub(now)), Urgent: true, Data: output.ContestStatus(*c, now)}
+}
+
+func statusLine(c *parse.Contest, now time.Time) string {
+	st := c.StateAt(now)
+	switch st {
+	case parse.StateUpcoming:
+		return fmt.Sprintf("upcoming, starts in %s", clock(c.Start.Sub(now)))
+	case parse.StateRunning:
+		if !c.Freeze.IsZero() {
+			return fmt.Sprintf("running, %s left (freeze in %s)", clock(c.End.Sub(now)), clock(c.Freeze.Sub(now)))
+		}
+		return fmt.Sprintf("running, %s left", clock(c.End.Sub(now)))
+	case parse.StateFrozen:
+		return fmt.Sprintf("frozen, %s left", clock(c.End.Sub(now)))
+	case parse.StateFinished:
+		return "finished"
+	}
+	return "state unknown"
+}
+
+func clock(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
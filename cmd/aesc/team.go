@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"aesc-client/output"
+	"aesc-client/submit"
+	"aesc-client/team"
+	"aesc-client/workspace"
+)
+
+func (a *app) member() (string, error) {
+	p, err := a.profile()
+	if err != nil {
+		return "", err
+	}
+	name := p.Member
+	if name == "" {
+		name = os.Getenv("USER")
+	}
+	if !team.ValidName(name) {
+		return "", errors.New("unknown teammate name: run `aesc team set --member NAME`")
+	}
+	return name, nil
+}
+
+func (a *app) board() (team.Board, error) {
+	p, err := a.profile()
+	if err != nil {
+		return nil, err
+	}
+	location := p.Team
+	if m, err := workspace.Find("."); err == nil && m.Team != "" {
+		location = m.TeamLocation()
+	}
+	return team.Open(location, p.TeamToken)
+}
+
+func (a *app) teamConfigured() bool {
+	p, err := a.profile()
+	if err != nil {
+		return false
+	}
+	if p.Team != "" {
+		return true
+	}
+	m, err := workspace.Find(".")
+	return err == nil && m.Team != ""
+}
+
+func (a *app) recordActivity(e *submit.Entry) {
+	if e.Member == "" || !a.teamConfigured() {
+		return
+	}
+	b, err := a.board()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "team: %v\n", err)
+		return
+	}
+	err = team.Update(b, e.Member, func(m *team.Member) {
+		m.Last = &team.Activity{Problem: e.Problem, File: e.File, Verdict: e.Verdict, Time: e.Time}
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "team: %v\n", err)
+	}
+}
+
+func cmdTeam(a *app, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "set":
+			return cmdTeamSet(a, args[1:])
+		case "serve":
+			return cmdTeamServe(a, args[1:])
+		case "status":
+			args = args[1:]
+		}
+	}
+	fs := a.flags("team")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	b, err := a.board()
+	if err != nil {
+		return err
+	}
+	members, err := b.Members()
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		return o.List(output.FromTeam(members))
+	}
+	if len(members) == 0 {
+		fmt.Println("nobody has claimed anything yet")
+		return nil
+	}
+	me, _ := a.member()
+	for _, tm := range output.FromTeam(members) {
+		mark := " "
+		if tm.Name == me {
+			mark = "*"
+		}
+		claims := strings.Join(tm.Claims, ", ")
+		if claims == "" {
+			claims = "-"
+		}
+		last := "-"
+		if tm.Last != nil {
+			verdict := tm.Last.Verdict
+			if verdict == "" {
+				verdict = "no verdict yet"
+			}
+			last = fmt.Sprintf("%s (%s) %s, %s ago", tm.Last.Problem, tm.Last.File, verdict, time.Since(tm.Last.Time).Round(time.Minute))
+		}
+		fmt.Printf("%s %-12s claims: %-12s last submit: %s\n", mark, tm.Name, claims, last)
+	}
+	return nil
+}
+
+func cmdTeamSet(a *app, args []string) error {
+	fs := a.flags("team set")
+	member := fs.String("member", "", "your name as shown to teammates")
+	sync := fs.String("sync", "", "shared directory or sync server URL")
+	name := fs.String("name", "", "team name as it appears in the standings")
+	token := fs.String("token", "", "token printed by `aesc team serve`")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *member == "" && *sync == "" && *name == "" && *token == "" {
+		return errors.New("usage: aesc team set [--member NAME] [--sync DIR|URL] [--token TOKEN] [--name TEAM]")
+	}
+	if *member != "" && !team.ValidName(*member) {
+		return fmt.Errorf("bad member name %q: use letters, digits, '.', '_' or '-'", *member)
+	}
+	cfg, err := a.config()
+	if err != nil {
+		return err
+	}
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	stored, err := cfg.Get(p.Name)
+	if err != nil {
+		return fmt.Errorf("%w: create one with `aesc profile add`", err)
+	}
+	if *member != "" {
+		stored.Member = *member
+	}
+	if *sync != "" {
+		stored.Team = *sync
+	}
+	if *name != "" {
+		stored.TeamName = *name
+	}
+	if *token != "" {
+		stored.TeamToken = *token
+	}
+	err = cfg.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("team", "%s: member %q, sync %s, standings name %q", p.Name, stored.Member, stored.Team, stored.TeamName)
+}
+
+func cmdTeamServe(a *app, args []string) error {
+	fs := a.flags("team serve")
+	addr := fs.String("addr", "127.0.0.1:8766", "address to listen on, use 0.0.0.0:8766 to share it on the LAN")
+	dir := fs.String("dir", "", "directory to keep the team state in (default: the profile directory)")
+	token := fs.String("token", "", "token teammates must send (default: a random one unless bound to loopback)")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *token == "" && !loopbackHost(*addr) {
+		*token, err = randomToken()
+		if err != nil {
+			return err
+		}
+	}
+	if *dir == "" {
+		_, err := a.profile()
+		if err != nil {
+			return err
+		}
+		*dir = a.prof.Path("team")
+	}
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", *addr, err)
+	}
+	srv := &http.Server{Handler: team.Handler(&team.DirBoard{Dir: *dir}, *token), ReadHeaderTimeout: 10 * time.Second}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	fmt.Fprintf(os.Stderr, "team sync server on http://%s, state in %s\n", ln.Addr(), *dir)
+	if *token != "" {
+		fmt.Fprintf(os.Stderr, "teammates connect with: aesc team set --sync http://HOST:%d --token %s\n", ln.Addr().(*net.TCPAddr).Port, *token)
+	}
+	err = srv.Serve(ln)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func cmdClaim(a *app, args []string) error {
+	fs := a.flags("claim")
+	release := fs.Bool("release", false, "drop the claim instead")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: aesc claim [--release] <problem>...")
+	}
+	me, err := a.member()
+	if err != nil {
+		return err
+	}
+	b, err := a.board()
+	if err != nil {
+		return err
+	}
+	var shorts []string
+	for _, ref := range fs.Args() {
+		short := strings.ToUpper(ref)
+		if pr, err := a.problem(ref); err == nil && pr.Short != "" {
+			short = pr.Short
+		}
+		shorts = append(shorts, short)
+	}
+	if !*release {
+		members, err := b.Members()
+		if err != nil {
+			return err
+		}
+		for _, short := range shorts {
+			var others []string
+			for _, name := range team.Claimers(members, short) {
+				if name != me {
+					others = append(others, name)
+				}
+			}
+			if len(others) > 0 {
+				sort.Strings(others)
+				fmt.Fprintf(os.Stderr, "warning: %s is already claimed by %s\n", short, strings.Join(others, ", "))
+			}
+		}
+	}
+	err = team.Update(b, me, func(m *team.Member) {
+		for _, short := range shorts {
+			if *release {
+				delete(m.Claims, short)
+			} else {
+				m.Claims[short] = time.Now()
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if *release {
+		return a.message("released", "%s released %s", me, strings.Join(shorts, ", "))
+	}
+	return a.message("claimed", "%s claimed %s", me, strings.Join(shorts, ", "))
+}
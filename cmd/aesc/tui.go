@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+
+	"aesc-client/parse"
+	"aesc-client/submit"
+	"aesc-client/tui"
+)
+
+type tuiSource struct {
+	a *app
+}
+
+func (s tuiSource) Contests() ([]parse.Contest, error) {
+	return s.a.contests()
+}
+
+func (s tuiSource) Problems(c parse.Contest) ([]parse.Problem, error) {
+	s.a.contestRef = c.URL
+	problems, err := s.a.problems()
+	if err != nil {
+		return nil, err
+	}
+	for i := range problems {
+		problems[i].URL = s.a.prof.URL(problems[i].URL)
+	}
+	return problems, nil
+}
+
+func (s tuiSource) Statement(p parse.Problem) (string, error) {
+	client, err := s.a.session()
+	if err != nil {
+		return "", err
+	}
+	return parse.FetchStatementToString(client, p.URL)
+}
+
+func (s tuiSource) Submissions(p parse.Problem) ([]parse.Submission, error) {
+	client, err := s.a.session()
+	if err != nil {
+		return nil, err
+	}
+	return submit.FetchSubmissions(client, p.URL)
+}
+
+func (s tuiSource) Submit(p parse.Problem, file string, force bool) ([]string, error) {
+	src, err := readSource(file, "", "")
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	c, err := s.a.check(&p, src, force, nil, func(w string) {
+		warnings = append(warnings, w)
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.a.send(&p, src, c)
+	return warnings, err
+}
+
+func cmdTUI(a *app, args []string) error {
+	fs := a.flags("tui")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if a.out().Structured() {
+		return errors.New("tui does not support --json/--jsonl")
+	}
+	p, err := a.profile()
+	if err != nil {
+		return err
+	}
+	_, err = a.session()
+	if err != nil {
+		return err
+	}
+	return tui.New(tuiSource{a}, p.Name).Run()
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/standings"
+	"aesc-client/virtual"
+)
+
+func (a *app) virtual() (*virtual.Session, error) {
+	_, err := a.profile()
+	if err != nil {
+		return nil, err
+	}
+	return virtual.Load(a.prof.Path("virtual.json"))
+}
+
+func cmdVirtual(a *app, args []string) error {
+	fs := a.flags("virtual")
+	duration := fs.Duration("duration", 5*time.Hour, "length of the virtual contest")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return cmdVirtualStatus(a, nil)
+	}
+	ref := fs.Arg(0)
+	switch ref {
+	case "status":
+		return cmdVirtualStatus(a, fs.Args()[1:])
+	case "finish", "result":
+		return cmdVirtualResult(a, ref == "finish", fs.Args()[1:])
+	}
+	err = fs.Parse(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *duration <= 0 {
+		return errors.New("usage: aesc virtual <contest> [--duration 5h] | virtual status | virtual finish | virtual result")
+	}
+	v, err := a.virtual()
+	if err != nil {
+		return err
+	}
+	if v != nil && v.Active(time.Now()) {
+		return fmt.Errorf("virtual contest %s is still running: finish it with `aesc virtual finish`", v.Name)
+	}
+	a.contestRef = ref
+	u, err := a.contestURL()
+	if err != nil {
+		return err
+	}
+	name := u
+	if contests, err := a.contests(); err == nil {
+		for _, c := range contests {
+			if a.prof.URL(c.URL) == u && c.Name != "" {
+				name = c.Name
+			}
+		}
+	}
+	final, err := a.fetchStandings(u)
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "warning: could not store the final standings now, will retry at the end: %v\n", err)
+	case len(final.Rows) == 0:
+		fmt.Fprintln(os.Stderr, "warning: the contest page has no standings table, placement will not be available")
+	}
+	v = virtual.New(u, name, time.Now(), *duration)
+	err = v.Save(a.prof.Path("virtual.json"))
+	if err != nil {
+		return err
+	}
+	return a.message("virtual", "virtual %s started, ends at %s; submissions are timed from now and the standings are hidden", name, v.End.Format("15:04:05"))
+}
+
+func cmdVirtualStatus(a *app, args []string) error {
+	fs := a.flags("virtual status")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	v, err := a.virtual()
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return errors.New("no virtual contest: start one with `aesc virtual <contest> --duration 5h`")
+	}
+	r, err := a.virtualResult(v, false)
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromVirtual(v, r))
+	}
+	now := time.Now()
+	if v.Active(now) {
+		fmt.Printf("%s: virtual %s elapsed, %s left\n", v.Name, clock(v.Elapsed(now)), clock(v.End.Sub(now)))
+	} else {
+		fmt.Printf("%s: virtual contest over, see `aesc virtual result`\n", v.Name)
+	}
+	printVirtualProblems(r)
+	return nil
+}
+
+func cmdVirtualResult(a *app, finish bool, args []string) error {
+	fs := a.flags("virtual result")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	v, err := a.virtual()
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return errors.New("no virtual contest: start one with `aesc virtual <contest> --duration 5h`")
+	}
+	if v.Active(time.Now()) && !finish {
+		return fmt.Errorf("virtual %s is still running: wait %s or end it with `aesc virtual finish`", v.Name, clock(time.Until(v.End)))
+	}
+	if !v.Finished {
+		if time.Now().Before(v.End) {
+			v.End = time.Now()
+		}
+		v.Finished = true
+		err = v.Save(a.prof.Path("virtual.json"))
+		if err != nil {
+			return err
+		}
+	}
+	r, err := a.virtualResult(v, true)
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		return o.Item(output.FromVirtual(v, r))
+	}
+	fmt.Printf("%s: virtual contest from %s to %s\n", v.Name, v.Start.Format("2006-01-02 15:04"), v.End.Format("15:04"))
+	printVirtualProblems(r)
+	if r.Scored {
+		fmt.Printf("score: %d\n", r.Score)
+	} else {
+		fmt.Printf("solved: %d, penalty: %d\n", r.Solved, r.Penalty)
+	}
+	if r.Pending > 0 {
+		fmt.Printf("%d submission(s) still without a verdict, run `aesc virtual result` again later\n", r.Pending)
+	}
+	if r.Teams > 0 {
+		fmt.Printf("would have placed #%d of %d\n", r.Place, r.Teams+1)
+	} else {
+		fmt.Println("no final standings stored, placement unknown")
+	}
+	return nil
+}
+
+func (a *app) virtualResult(v *virtual.Session, withStandings bool) (virtual.Result, error) {
+	l, err := a.submitLog()
+	if err != nil {
+		return virtual.Result{}, err
+	}
+	err = a.refreshVerdicts(l)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: could not refresh verdicts: %v\n", err)
+	}
+	if !withStandings {
+		return v.Compute(l.Entries(), nil), nil
+	}
+	final, err := a.finalStandings(v.Contest)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return v.Compute(l.Entries(), final), nil
+}
+
+func (a *app) finalStandings(contest string) (*parse.Standings, error) {
+	snaps, err := standings.Open(a.prof.Path("standings"), contest)
+	if err != nil {
+		return nil, err
+	}
+	var final *parse.Standings
+	if all := snaps.Snapshots(); len(all) > 0 {
+		final = all[len(all)-1].Standings
+	} else {
+		final, err = a.fetchStandings(contest)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err := a.profile()
+	if err != nil || p.TeamName == "" {
+		return final, nil
+	}
+	others := *final
+	others.Rows = nil
+	for _, r := range final.Rows {
+		if !strings.EqualFold(r.Team, p.TeamName) {
+			others.Rows = append(others.Rows, r)
+		}
+	}
+	return &others, nil
+}
+
+func printVirtualProblems(r virtual.Result) {
+	for _, p := range r.Problems {
+		switch {
+		case p.Solved:
+			fmt.Printf("  %-4s + at %d:%02d after %d attempt(s)\n", p.Problem, p.Minute/60, p.Minute%60, p.Attempts)
+		case p.Score > 0:
+			fmt.Printf("  %-4s %d points, %d attempt(s)\n", p.Problem, p.Score, p.Attempts)
+		default:
+			fmt.Printf("  %-4s - %d attempt(s)\n", p.Problem, p.Attempts)
+		}
+	}
+}
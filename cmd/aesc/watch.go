@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"aesc-client/localtest"
+	"aesc-client/notify"
+	"aesc-client/output"
+	"aesc-client/parse"
+	"aesc-client/submit"
+	"aesc-client/watch"
+)
+
+func (a *app) problemWithSamples(ref string) (*parse.Problem, []parse.Sample, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, nil, err
+	}
+	pr, err := a.problem(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+	samples, err := fetchSamples(client, pr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, samples, nil
+}
+
+func fetchSamples(client *http.Client, pr *parse.Problem) ([]parse.Sample, error) {
+	text, err := parse.FetchStatementToString(client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	parse.FillFromStatement(pr, text)
+	return parse.FetchSamples(client, pr.URL)
+}
+
+func runSamples(ctx context.Context, pr *parse.Problem, samples []parse.Sample, file string) ([]localtest.Result, error) {
+	r, err := localtest.Build(ctx, file, pr)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return localtest.Check(ctx, r, samples), nil
+}
+
+func printSamples(results []localtest.Result) {
+	for _, res := range results {
+		if res.Passed {
+			fmt.Printf("sample %d: ok (%v)\n", res.Sample, res.Elapsed.Round(time.Millisecond))
+			continue
+		}
+		if res.Err != nil {
+			fmt.Printf("sample %d: %v\n", res.Sample, res.Err)
+			continue
+		}
+		fmt.Printf("sample %d: wrong answer\n--- want\n%s--- got\n%s", res.Sample, res.Want, res.Got)
+	}
+}
+
+func (a *app) submitAndWait(ctx context.Context, pr *parse.Problem, src *source, c *submit.Checker) (*parse.Submission, error) {
+	client, err := a.session()
+	if err != nil {
+		return nil, err
+	}
+	before, err := submit.FetchSubmissions(client, pr.URL)
+	if err != nil {
+		return nil, err
+	}
+	e, err := a.send(pr, src, c)
+	if err != nil {
+		return nil, err
+	}
+	a.progress("%s: solution submitted, waiting for verdict", pr.Name)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+	s, err := submit.WaitVerdict(ctx, client, pr.URL, submit.KnownIDs(before), 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	a.notify(notify.Event{Kind: notify.Verdict, Title: pr.Name + ": " + s.Verdict, Body: verdictLine(s), Data: output.FromSubmission(pr.Short, *s)})
+	return s, a.logVerdict(e, s)
+}
+
+func printVerdict(s *parse.Submission) {
+	fmt.Println(verdictLine(s))
+}
+
+func verdictLine(s *parse.Submission) string {
+	line := fmt.Sprintf("verdict: %s", s.Verdict)
+	if s.Test > 0 && !s.Accepted() {
+		line += fmt.Sprintf(" on test %d", s.Test)
+	}
+	if s.Score > 0 {
+		line += fmt.Sprintf(", score %d", s.Score)
+	}
+	return line
+}
+
+func cmdTest(a *app, args []string) error {
+	fs := a.flags("test")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: aesc test <problem> <file>")
+	}
+	pr, samples, err := a.problemWithSamples(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		return fmt.Errorf("%s: no samples found in the statement", pr.Name)
+	}
+	results, err := runSamples(context.Background(), pr, samples, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	if o := a.out(); o.Structured() {
+		err = o.List(output.FromSampleResults(results))
+		if err != nil {
+			return err
+		}
+	} else {
+		printSamples(results)
+	}
+	if !localtest.Passed(results) {
+		return errors.New("samples failed")
+	}
+	return nil
+}
+
+func cmdWatch(a *app, args []string) error {
+	fs := a.flags("watch")
+	debounce := fs.Duration("debounce", 500*time.Millisecond, "wait this long after the last save")
+	confirm := fs.Bool("confirm", false, "ask before submitting")
+	clarEvery := fs.Duration("clar-interval", time.Minute, "how often to check for jury answers (0 disables)")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: aesc watch [--debounce D] [--confirm] <problem> <file>")
+	}
+	file := fs.Arg(1)
+	pr, samples, err := a.problemWithSamples(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(samples) == 0 {
+		fmt.Fprintf(os.Stderr, "warning: %s has no samples, every save will be submitted\n", pr.Name)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	changes, err := watch.Changes(ctx, file, *debounce)
+	if err != nil {
+		return err
+	}
+	var clarTick <-chan time.Time
+	if *clarEvery > 0 {
+		t := time.NewTicker(*clarEvery)
+		defer t.Stop()
+		clarTick = t.C
+	}
+	client, err := a.session()
+	if err != nil {
+		return err
+	}
+	o := a.out()
+	a.progress("watching %s for %s, press Ctrl-C to stop", file, pr.Name)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-clarTick:
+			_, fresh, err := a.newAnswers()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "clarifications: %v\n", err)
+			}
+			for _, c := range fresh {
+				if o.Structured() {
+					err := o.Event(output.NewEvent("clarification", output.FromClarification(c)))
+					if err != nil {
+						return err
+					}
+					continue
+				}
+				printClarification(c)
+			}
+		case _, ok := <-changes:
+			if !ok {
+				return nil
+			}
+			if o.Structured() {
+				err := o.Event(output.NewEvent("changed", map[string]string{"file": file, "problem": pr.Short}))
+				if err != nil {
+					return err
+				}
+			} else {
+				fmt.Printf("\n%s changed at %s\n", file, time.Now().Format("15:04:05"))
+			}
+			if fresh, err := fetchSamples(client, pr); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: could not refresh samples, using the previous ones: %v\n", err)
+			} else {
+				samples = fresh
+			}
+			results, err := runSamples(ctx, pr, samples, file)
+			if err != nil {
+				if o.Structured() {
+					err := o.Event(output.NewEvent("build_failed", output.Message{Message: err.Error()}))
+					if err != nil {
+						return err
+					}
+				} else {
+					fmt.Println(err)
+				}
+				continue
+			}
+			if o.Structured() {
+				err := o.Event(output.NewEvent("samples", output.FromSampleResults(results)))
+				if err != nil {
+					return err
+				}
+			} else {
+				printSamples(results)
+			}
+			if !localtest.Passed(results) {
+				continue
+			}
+			if *confirm && !promptTTY("samples passed, submit?") {
+				continue
+			}
+			src, err := readSource(file, "", "")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "submit: %v\n", err)
+				continue
+			}
+			c, err := a.check(pr, src, false, askTTY, warnStderr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "submit: %v\n", err)
+				continue
+			}
+			s, err := a.submitAndWait(ctx, pr, src, c)
+			if errors.Is(err, submit.ErrRefused) || errors.Is(err, submit.ErrNotConfirmed) {
+				fmt.Fprintf(os.Stderr, "not submitted: %v\n", err)
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "submit: %v\n", err)
+				continue
+			}
+			if o.Structured() {
+				err := o.Event(output.NewEvent("verdict", output.FromSubmission(pr.Short, *s)))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			printVerdict(s)
+		}
+	}
+}
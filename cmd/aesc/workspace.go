@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aesc-client/workspace"
+)
+
+func cmdWorkspace(a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: aesc workspace init|map ...")
+	}
+	switch args[0] {
+	case "init":
+		return cmdWorkspaceInit(a, args[1:])
+	case "map":
+		return cmdWorkspaceMap(a, args[1:])
+	}
+	return fmt.Errorf("unknown workspace command %q", args[0])
+}
+
+func cmdWorkspaceInit(a *app, args []string) error {
+	fs := a.flags("workspace init")
+	ext := fs.String("ext", "cpp", "extension of the solution files to map")
+	teamDir := fs.String("team", "", "shared team directory (relative to the workspace) or sync server URL")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	problems, err := a.problems()
+	if err != nil {
+		return err
+	}
+	u, err := a.contestURL()
+	if err != nil {
+		return err
+	}
+	m := workspace.New(dir)
+	m.Profile = a.prof.Name
+	m.Contest = strings.TrimPrefix(u, a.prof.Server)
+	m.Team = *teamDir
+	for i, pr := range problems {
+		name := pr.Short
+		if name == "" {
+			name = fmt.Sprint(i + 1)
+		}
+		m.Files[name+"."+strings.TrimPrefix(*ext, ".")] = pr.URL
+	}
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	err = m.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("workspace", "wrote %s with %d problem(s)", filepath.Join(dir, workspace.FileName), len(problems))
+}
+
+func cmdWorkspaceMap(a *app, args []string) error {
+	fs := a.flags("workspace map")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("usage: aesc workspace map <file> <problem>")
+	}
+	file := fs.Arg(0)
+	m, err := workspace.Find(filepath.Dir(file))
+	if err != nil {
+		return fmt.Errorf("%w: run `aesc workspace init` first", err)
+	}
+	if m.Profile != "" && a.profileName == "" {
+		a.profileName = m.Profile
+	}
+	if m.Contest != "" && a.contestRef == "" {
+		a.contestRef = m.Contest
+	}
+	pr, err := a.problem(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	err = m.Map(file, strings.TrimPrefix(pr.URL, a.prof.Server))
+	if err != nil {
+		return err
+	}
+	err = m.Save()
+	if err != nil {
+		return err
+	}
+	return a.message("workspace", "%s -> %s", file, pr.Name)
+}
@@ -3,7 +3,9 @@ module aesc-client
 go 1.25.1
 
 require (
-	github.com/PuerkitoBio/goquery v1.10.3 // indirect
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	golang.org/x/net v0.44.0 // indirect
+	github.com/PuerkitoBio/goquery v1.10.3
+	golang.org/x/net v0.44.0
+	golang.org/x/text v0.29.0
 )
+
+require github.com/andybalholm/cascadia v1.3.3 // indirect
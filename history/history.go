@@ -0,0 +1,65 @@
+package history
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aesc-client/textdiff"
+)
+
+type Store struct {
+	dir string
+}
+
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:10])+".txt")
+}
+
+func (s *Store) Previous(key string) (string, time.Time, bool, error) {
+	p := s.path(key)
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("read %s: %w", p, err)
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	return string(b), st.ModTime(), true, nil
+}
+
+func (s *Store) Record(key, name, text string) (string, error) {
+	prev, when, ok, err := s.Previous(key)
+	if err != nil {
+		return "", err
+	}
+	diff := ""
+	if ok {
+		diff = textdiff.Unified(name+" ("+when.Format("2006-01-02 15:04:05")+")", name+" (now)", prev, text, 3)
+		if diff == "" {
+			return "", nil
+		}
+	}
+	err = os.MkdirAll(s.dir, 0o700)
+	if err != nil {
+		return "", fmt.Errorf("mkdir %s: %w", s.dir, err)
+	}
+	err = os.WriteFile(s.path(key), []byte(text), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write statement history: %w", err)
+	}
+	return diff, nil
+}
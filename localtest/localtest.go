@@ -0,0 +1,165 @@
+package localtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aesc-client/parse"
+)
+
+const DefaultTimeLimit = 2 * time.Second
+
+type Runner struct {
+	Input     string
+	Output    string
+	TimeLimit time.Duration
+
+	dir string
+	cmd []string
+}
+
+type Result struct {
+	Sample  int
+	Passed  bool
+	Got     string
+	Want    string
+	Elapsed time.Duration
+	Err     error
+}
+
+func Build(ctx context.Context, src string, p *parse.Problem) (*Runner, error) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "aesc-build-")
+	if err != nil {
+		return nil, fmt.Errorf("create build dir: %w", err)
+	}
+	r := &Runner{dir: dir, TimeLimit: DefaultTimeLimit}
+	if p != nil {
+		r.Input, r.Output = p.Input, p.Output
+		if p.TimeLimit > 0 {
+			r.TimeLimit = p.TimeLimit
+		}
+	}
+	bin := filepath.Join(dir, "solution")
+	var build []string
+	switch strings.ToLower(filepath.Ext(src)) {
+	case ".cpp", ".cc", ".cxx":
+		build = []string{"g++", "-O2", "-std=c++17", "-o", bin, src}
+		r.cmd = []string{bin}
+	case ".c":
+		build = []string{"gcc", "-O2", "-o", bin, src, "-lm"}
+		r.cmd = []string{bin}
+	case ".pas":
+		build = []string{"fpc", "-O2", "-o" + bin, src}
+		r.cmd = []string{bin}
+	case ".py":
+		r.cmd = []string{"python3", src}
+	case ".java":
+		build = []string{"javac", "-d", dir, src}
+		r.cmd = []string{"java", "-cp", dir, strings.TrimSuffix(filepath.Base(src), ".java")}
+	case ".cs":
+		build = []string{"mcs", "-out:" + bin + ".exe", src}
+		r.cmd = []string{"mono", bin + ".exe"}
+	default:
+		r.Close()
+		return nil, fmt.Errorf("don't know how to run %s", filepath.Base(src))
+	}
+	if build != nil {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, build[0], build[1:]...)
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("compile %s: %w\n%s", filepath.Base(src), err, stderr.String())
+		}
+	}
+	return r, nil
+}
+
+func (r *Runner) Close() error {
+	return os.RemoveAll(r.dir)
+}
+
+func (r *Runner) Run(ctx context.Context, input string) (string, time.Duration, error) {
+	work, err := os.MkdirTemp(r.dir, "run-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(work)
+	ctx, cancel := context.WithTimeout(ctx, 2*r.TimeLimit)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, r.cmd[0], r.cmd[1:]...)
+	cmd.Dir = work
+	if r.Input != "" {
+		err := os.WriteFile(filepath.Join(work, r.Input), []byte(input), 0o600)
+		if err != nil {
+			return "", 0, err
+		}
+	} else {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start)
+	if ctx.Err() != nil || elapsed > r.TimeLimit {
+		return stdout.String(), elapsed, errors.New("time limit exceeded")
+	}
+	if err != nil {
+		return stdout.String(), elapsed, fmt.Errorf("runtime error: %w\n%s", err, stderr.String())
+	}
+	if r.Output == "" {
+		return stdout.String(), elapsed, nil
+	}
+	b, err := os.ReadFile(filepath.Join(work, r.Output))
+	if err != nil {
+		return "", elapsed, fmt.Errorf("read %s: %w", r.Output, err)
+	}
+	return string(b), elapsed, nil
+}
+
+func Check(ctx context.Context, r *Runner, samples []parse.Sample) []Result {
+	var out []Result
+	for i, s := range samples {
+		got, elapsed, err := r.Run(ctx, s.Input)
+		res := Result{Sample: i + 1, Got: got, Want: s.Output, Elapsed: elapsed, Err: err}
+		res.Passed = err == nil && Equal(got, s.Output)
+		out = append(out, res)
+	}
+	return out
+}
+
+func Equal(got, want string) bool {
+	g, w := strings.Fields(got), strings.Fields(want)
+	if len(g) != len(w) {
+		return false
+	}
+	for i := range g {
+		if g[i] != w[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
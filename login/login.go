@@ -12,6 +12,7 @@ import (
 	"io"
 	"errors"
 	"bufio"
+	"sync"
 )
 
 func ReadLogpass(logpassPath string) (login string, password string, err error) {
@@ -147,3 +148,88 @@ func LoadSessionCookies(jar http.CookieJar, baseURL, inPath string) error {
 	jar.SetCookies(u, cookies)
 	return nil
 }
+
+func WriteLogpass(logpassPath, login, password string) error {
+	login = strings.TrimSpace(login)
+	password = strings.TrimSpace(password)
+	if login == "" || password == "" {
+		return errors.New("login or password is empty")
+	}
+	fdir := filepath.Dir(logpassPath)
+	if fdir != "." {
+		err := os.MkdirAll(fdir, 0o700)
+		if err != nil {
+			return fmt.Errorf("mkdir %s: %w", fdir, err)
+		}
+	}
+	err := os.WriteFile(logpassPath, []byte(login+"\n"+password+"\n"), 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", logpassPath, err)
+	}
+	return nil
+}
+
+var ErrSessionUnknown = errors.New("session state unknown")
+
+var checkedSessions sync.Map
+
+func SessionValid(client *http.Client, base, loginPath, checkPath string) (bool, error) {
+	checkURL := strings.TrimRight(base, "/") + checkPath
+	req, err := http.NewRequest("GET", checkURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("create session check request: %w", err)
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "msu-client/0.1")
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSessionUnknown, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if strings.HasPrefix(resp.Header.Get("Warning"), "111 ") {
+		return false, fmt.Errorf("%w: %s answered from the local cache", ErrSessionUnknown, checkURL)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return false, nil
+	}
+	return resp.Request.URL.Path != loginPath, nil
+}
+
+func OpenSession(client *http.Client, base, loginPath, checkPath, logpassPath, cookiesPath string) error {
+	_, err := os.Stat(cookiesPath)
+	if err == nil {
+		err = LoadSessionCookies(client.Jar, base, cookiesPath)
+		if err != nil {
+			return err
+		}
+		if _, done := checkedSessions.Load(cookiesPath); done {
+			return nil
+		}
+		ok, err := SessionValid(client, base, loginPath, checkPath)
+		if errors.Is(err, ErrSessionUnknown) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			checkedSessions.Store(cookiesPath, true)
+			return nil
+		}
+	}
+	name, password, err := ReadLogpass(logpassPath)
+	if err != nil {
+		return err
+	}
+	_, err = TryLogin(client, base, loginPath, name, password)
+	if err != nil {
+		return err
+	}
+	err = SaveCookies(client.Jar, base, cookiesPath)
+	if err != nil {
+		return err
+	}
+	checkedSessions.Store(cookiesPath, true)
+	return nil
+}
@@ -0,0 +1,80 @@
+package news
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"aesc-client/parse"
+)
+
+type Store struct {
+	Seen  map[string]bool      `json:"seen"`
+	Items []parse.Announcement `json:"items"`
+
+	path string
+}
+
+func Load(path string) (*Store, error) {
+	s := &Store{Seen: map[string]bool{}, path: path}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if s.Seen == nil {
+		s.Seen = map[string]bool{}
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	fdir := filepath.Dir(s.path)
+	err := os.MkdirAll(fdir, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", fdir, err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode news: %w", err)
+	}
+	err = os.WriteFile(s.path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) Merge(items []parse.Announcement) []parse.Announcement {
+	known := map[string]bool{}
+	for _, it := range s.Items {
+		known[it.ID] = true
+	}
+	var unseen []parse.Announcement
+	for _, it := range items {
+		if !known[it.ID] {
+			s.Items = append(s.Items, it)
+			known[it.ID] = true
+		}
+		if !s.Seen[it.ID] {
+			unseen = append(unseen, it)
+		}
+	}
+	sort.SliceStable(s.Items, func(i, j int) bool { return s.Items[i].Time.Before(s.Items[j].Time) })
+	sort.SliceStable(unseen, func(i, j int) bool { return unseen[i].Time.Before(unseen[j].Time) })
+	return unseen
+}
+
+func (s *Store) MarkSeen(items []parse.Announcement) {
+	for _, it := range items {
+		s.Seen[it.ID] = true
+	}
+}
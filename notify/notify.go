@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	Verdict          = "verdict"
+	Clarification    = "clarification"
+	StatementChanged = "statement_changed"
+	FreezeSoon       = "freeze_soon"
+	ContestEnding    = "contest_ending"
+)
+
+type Event struct {
+	Kind   string    `json:"event"`
+	Title  string    `json:"title"`
+	Body   string    `json:"body,omitempty"`
+	Urgent bool      `json:"urgent,omitempty"`
+	Time   time.Time `json:"time"`
+	Data   any       `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+type Bell struct {
+	W io.Writer
+}
+
+func (b Bell) Notify(ctx context.Context, ev Event) error {
+	_, err := io.WriteString(b.W, "\a")
+	return err
+}
+
+type Desktop struct {
+	Command string
+}
+
+func (d Desktop) Notify(ctx context.Context, ev Event) error {
+	name := d.Command
+	if name == "" {
+		name = "notify-send"
+	}
+	urgency := "normal"
+	if ev.Urgent {
+		urgency = "critical"
+	}
+	out, err := exec.CommandContext(ctx, name, "-a", "aesc", "-u", urgency, "--", ev.Title, ev.Body).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w Webhook) Notify(ctx context.Context, ev Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.URL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+type Command struct {
+	Line string
+}
+
+func (c Command) Notify(ctx context.Context, ev Event) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Line)
+	cmd.Stdin = bytes.NewReader(b)
+	cmd.Env = append(os.Environ(), "AESC_EVENT="+ev.Kind, "AESC_TITLE="+ev.Title, "AESC_BODY="+ev.Body)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", c.Line, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+type Multi []Notifier
+
+func (m Multi) Notify(ctx context.Context, ev Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, ev); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func Parse(spec string) (Notifier, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "bell":
+		return Bell{W: os.Stderr}, nil
+	case "desktop":
+		return Desktop{Command: arg}, nil
+	case "webhook":
+		if !strings.HasPrefix(arg, "http://") && !strings.HasPrefix(arg, "https://") {
+			return nil, fmt.Errorf("webhook needs an http(s) URL, got %q", arg)
+		}
+		return Webhook{URL: arg, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "command", "exec":
+		if arg == "" {
+			return nil, errors.New("command notifier needs a command line")
+		}
+		return Command{Line: arg}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier %q: use bell, desktop[:CMD], webhook:URL or command:CMD", spec)
+}
+
+func New(specs []string) (Multi, error) {
+	var m Multi
+	for _, s := range specs {
+		if s == "none" {
+			continue
+		}
+		n, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, n)
+	}
+	return m, nil
+}
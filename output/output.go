@@ -0,0 +1,70 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+type Format int
+
+const (
+	Text Format = iota
+	JSON
+	JSONL
+)
+
+type Printer struct {
+	w      io.Writer
+	format Format
+}
+
+func New(w io.Writer, format Format) *Printer {
+	return &Printer{w: w, format: format}
+}
+
+func (p *Printer) Structured() bool {
+	return p.format != Text
+}
+
+func (p *Printer) List(items any) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("output list: %T is not a slice", items)
+	}
+	if p.format == JSON {
+		if v.IsNil() {
+			items = []any{}
+		}
+		return p.encode(items, true)
+	}
+	for i := range v.Len() {
+		err := p.encode(v.Index(i).Interface(), false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) Item(item any) error {
+	return p.encode(item, p.format == JSON)
+}
+
+func (p *Printer) Event(item any) error {
+	return p.encode(item, false)
+}
+
+func (p *Printer) encode(v any, indent bool) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetEscapeHTML(false)
+	if indent {
+		enc.SetIndent("", "  ")
+	}
+	err := enc.Encode(v)
+	if err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	return nil
+}
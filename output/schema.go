@@ -0,0 +1,414 @@
+package output
+
+import (
+	"sort"
+	"time"
+
+	"aesc-client/localtest"
+	"aesc-client/parse"
+	"aesc-client/standings"
+	"aesc-client/stats"
+	"aesc-client/team"
+	"aesc-client/virtual"
+)
+
+type Contest struct {
+	Name            string     `json:"name"`
+	URL             string     `json:"url"`
+	Start           *time.Time `json:"start,omitempty"`
+	End             *time.Time `json:"end,omitempty"`
+	Freeze          *time.Time `json:"freeze,omitempty"`
+	DurationSec     int64      `json:"duration_sec,omitempty"`
+	State           string     `json:"state,omitempty"`
+	ServerOffsetSec int64      `json:"server_offset_sec,omitempty"`
+	RemainingSec    *int64     `json:"remaining_sec,omitempty"`
+	UntilFreezeSec  *int64     `json:"until_freeze_sec,omitempty"`
+}
+
+type Problem struct {
+	Short         string `json:"short"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	TimeLimitMS   int64  `json:"time_limit_ms,omitempty"`
+	MemoryLimitMB int64  `json:"memory_limit_mb,omitempty"`
+	Input         string `json:"input"`
+	Output        string `json:"output"`
+	Solved        bool   `json:"solved"`
+	Attempts      int    `json:"attempts"`
+	Score         int    `json:"score,omitempty"`
+	MaxScore      int    `json:"max_score,omitempty"`
+}
+
+type Attachment struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+	Path string `json:"path,omitempty"`
+}
+
+type Statement struct {
+	Problem     string       `json:"problem"`
+	URL         string       `json:"url"`
+	Format      string       `json:"format"`
+	Text        string       `json:"text"`
+	PDF         bool         `json:"pdf"`
+	Attachments []Attachment `json:"attachments"`
+	Diff        string       `json:"diff,omitempty"`
+}
+
+type Submission struct {
+	ID       string     `json:"id"`
+	Problem  string     `json:"problem"`
+	Time     *time.Time `json:"time,omitempty"`
+	Language string     `json:"language,omitempty"`
+	Verdict  string     `json:"verdict"`
+	Pending  bool       `json:"pending"`
+	Accepted bool       `json:"accepted"`
+	Test     int        `json:"test,omitempty"`
+	Score    int        `json:"score,omitempty"`
+}
+
+type ProblemResult struct {
+	Solved   bool `json:"solved"`
+	Attempts int  `json:"attempts"`
+	TimeMin  int  `json:"time_min,omitempty"`
+	Score    int  `json:"score,omitempty"`
+}
+
+type StandingsRow struct {
+	Place   int                      `json:"place"`
+	Team    string                   `json:"team"`
+	Solved  int                      `json:"solved"`
+	Penalty int                      `json:"penalty"`
+	Score   int                      `json:"score,omitempty"`
+	Results map[string]ProblemResult `json:"results"`
+}
+
+type Standings struct {
+	Problems []string       `json:"problems"`
+	Rows     []StandingsRow `json:"rows"`
+}
+
+type StandingsPoint struct {
+	Time   time.Time `json:"time"`
+	Place  int       `json:"place"`
+	Solved int       `json:"solved"`
+}
+
+type StandingsHistory struct {
+	Team   string           `json:"team"`
+	Points []StandingsPoint `json:"points"`
+}
+
+type VirtualProblem struct {
+	Problem  string `json:"problem"`
+	Solved   bool   `json:"solved"`
+	Attempts int    `json:"attempts"`
+	Minute   int    `json:"minute,omitempty"`
+	Score    int    `json:"score,omitempty"`
+}
+
+type Virtual struct {
+	Contest  string           `json:"contest"`
+	Name     string           `json:"name,omitempty"`
+	Start    time.Time        `json:"start"`
+	End      time.Time        `json:"end"`
+	Finished bool             `json:"finished"`
+	Solved   int              `json:"solved"`
+	Penalty  int              `json:"penalty"`
+	Score    int              `json:"score,omitempty"`
+	Pending  int              `json:"pending,omitempty"`
+	Place    int              `json:"place,omitempty"`
+	Teams    int              `json:"teams,omitempty"`
+	Problems []VirtualProblem `json:"problems"`
+}
+
+type Announcement struct {
+	ID   string     `json:"id"`
+	Time *time.Time `json:"time,omitempty"`
+	Text string     `json:"text"`
+}
+
+type Clarification struct {
+	ID       string     `json:"id"`
+	Time     *time.Time `json:"time,omitempty"`
+	Problem  string     `json:"problem"`
+	Question string     `json:"question"`
+	Answer   string     `json:"answer,omitempty"`
+	Answered bool       `json:"answered"`
+}
+
+type SampleResult struct {
+	Sample    int    `json:"sample"`
+	Passed    bool   `json:"passed"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+	Error     string `json:"error,omitempty"`
+	Got       string `json:"got,omitempty"`
+	Want      string `json:"want,omitempty"`
+}
+
+type Profile struct {
+	Name    string `json:"name"`
+	Server  string `json:"server"`
+	Current bool   `json:"current"`
+	Contest string `json:"contest,omitempty"`
+}
+
+type Message struct {
+	OK      bool   `json:"ok"`
+	Event   string `json:"event,omitempty"`
+	Message string `json:"message"`
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func FromContest(c parse.Contest) Contest {
+	return Contest{
+		Name:            c.Name,
+		URL:             c.URL,
+		Start:           timePtr(c.Start),
+		End:             timePtr(c.End),
+		Freeze:          timePtr(c.Freeze),
+		DurationSec:     int64(c.Duration / time.Second),
+		State:           string(c.StateAt(c.Now())),
+		ServerOffsetSec: int64(c.ServerOffset / time.Second),
+	}
+}
+
+func ContestStatus(c parse.Contest, now time.Time) Contest {
+	out := FromContest(c)
+	out.State = string(c.StateAt(now))
+	if !c.End.IsZero() {
+		left := int64(max(c.End.Sub(now), 0) / time.Second)
+		out.RemainingSec = &left
+	}
+	if !c.Freeze.IsZero() {
+		left := int64(max(c.Freeze.Sub(now), 0) / time.Second)
+		out.UntilFreezeSec = &left
+	}
+	return out
+}
+
+func FromContests(cs []parse.Contest) []Contest {
+	out := make([]Contest, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, FromContest(c))
+	}
+	return out
+}
+
+func FromProblem(p parse.Problem) Problem {
+	return Problem{
+		Short:         p.Short,
+		Name:          p.Name,
+		URL:           p.URL,
+		TimeLimitMS:   p.TimeLimit.Milliseconds(),
+		MemoryLimitMB: p.MemoryLimit >> 20,
+		Input:         p.Input,
+		Output:        p.Output,
+		Solved:        p.Solved,
+		Attempts:      p.Attempts,
+		Score:         p.Score,
+		MaxScore:      p.MaxScore,
+	}
+}
+
+func FromProblems(ps []parse.Problem) []Problem {
+	out := make([]Problem, 0, len(ps))
+	for _, p := range ps {
+		out = append(out, FromProblem(p))
+	}
+	return out
+}
+
+func FromStatement(p parse.Problem, st *parse.Statement, format, text string) Statement {
+	out := Statement{
+		Problem:     p.Short,
+		URL:         p.URL,
+		Format:      format,
+		Text:        text,
+		PDF:         st.PDF != nil,
+		Attachments: []Attachment{},
+	}
+	for _, at := range st.Attachments {
+		out.Attachments = append(out.Attachments, Attachment{Name: at.Name, Kind: at.Kind, URL: at.URL, Path: at.Path})
+	}
+	return out
+}
+
+func FromSubmission(problem string, s parse.Submission) Submission {
+	if s.Problem != "" {
+		problem = s.Problem
+	}
+	return Submission{
+		ID:       s.ID,
+		Problem:  problem,
+		Time:     timePtr(s.Time),
+		Language: s.Language,
+		Verdict:  s.Verdict,
+		Pending:  s.Pending(),
+		Accepted: s.Accepted(),
+		Test:     s.Test,
+		Score:    s.Score,
+	}
+}
+
+func FromSubmissions(problem string, subs []parse.Submission) []Submission {
+	out := make([]Submission, 0, len(subs))
+	for _, s := range subs {
+		out = append(out, FromSubmission(problem, s))
+	}
+	return out
+}
+
+func FromStandings(st *parse.Standings) Standings {
+	out := Standings{Problems: st.Problems, Rows: []StandingsRow{}}
+	if out.Problems == nil {
+		out.Problems = []string{}
+	}
+	for _, r := range st.Rows {
+		row := StandingsRow{Place: r.Place, Team: r.Team, Solved: r.Solved, Penalty: r.Penalty, Score: r.Score, Results: map[string]ProblemResult{}}
+		for k, v := range r.Results {
+			row.Results[k] = ProblemResult{Solved: v.Solved, Attempts: v.Attempts, TimeMin: v.Time, Score: v.Score}
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return out
+}
+
+func FromSeries(s standings.Series) StandingsHistory {
+	out := StandingsHistory{Team: s.Team, Points: []StandingsPoint{}}
+	for _, p := range s.Points {
+		out.Points = append(out.Points, StandingsPoint{Time: p.Time, Place: p.Place, Solved: p.Solved})
+	}
+	return out
+}
+
+func FromVirtual(s *virtual.Session, r virtual.Result) Virtual {
+	out := Virtual{Contest: s.Contest, Name: s.Name, Start: s.Start, End: s.End, Finished: s.Finished, Solved: r.Solved, Penalty: r.Penalty, Score: r.Score, Pending: r.Pending, Place: r.Place, Teams: r.Teams, Problems: []VirtualProblem{}}
+	for _, p := range r.Problems {
+		out.Problems = append(out.Problems, VirtualProblem{Problem: p.Problem, Solved: p.Solved, Attempts: p.Attempts, Minute: p.Minute, Score: p.Score})
+	}
+	return out
+}
+
+func FromAnnouncements(items []parse.Announcement) []Announcement {
+	out := make([]Announcement, 0, len(items))
+	for _, it := range items {
+		out = append(out, Announcement{ID: it.ID, Time: timePtr(it.Time), Text: it.Text})
+	}
+	return out
+}
+
+func FromClarification(c parse.Clarification) Clarification {
+	return Clarification{ID: c.ID, Time: timePtr(c.Time), Problem: c.Problem, Question: c.Question, Answer: c.Answer, Answered: c.Answered}
+}
+
+func FromClarifications(items []parse.Clarification) []Clarification {
+	out := make([]Clarification, 0, len(items))
+	for _, c := range items {
+		out = append(out, FromClarification(c))
+	}
+	return out
+}
+
+type Event struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data,omitempty"`
+}
+
+func NewEvent(name string, data any) Event {
+	return Event{Event: name, Time: time.Now(), Data: data}
+}
+
+func FromSampleResults(results []localtest.Result) []SampleResult {
+	out := make([]SampleResult, 0, len(results))
+	for _, r := range results {
+		sr := SampleResult{Sample: r.Sample, Passed: r.Passed, ElapsedMS: r.Elapsed.Milliseconds()}
+		if r.Err != nil {
+			sr.Error = r.Err.Error()
+		}
+		if !r.Passed {
+			sr.Got, sr.Want = r.Got, r.Want
+		}
+		out = append(out, sr)
+	}
+	return out
+}
+
+type ProblemStats struct {
+	Contest      string `json:"contest,omitempty"`
+	Problem      string `json:"problem"`
+	URL          string `json:"url"`
+	Attempts     int    `json:"attempts"`
+	Solved       bool   `json:"solved"`
+	AttemptsToAC int    `json:"attempts_to_ac,omitempty"`
+	TimeToACSec  *int64 `json:"time_to_ac_sec,omitempty"`
+}
+
+type Stats struct {
+	Submissions     int            `json:"submissions"`
+	Tried           int            `json:"tried"`
+	Solved          int            `json:"solved"`
+	Contests        int            `json:"contests"`
+	MeanTimeToACSec int64          `json:"mean_time_to_ac_sec"`
+	Verdicts        map[string]int `json:"verdicts"`
+	Problems        []ProblemStats `json:"problems"`
+}
+
+func FromStats(s stats.Summary) Stats {
+	out := Stats{
+		Submissions:     s.Submissions,
+		Tried:           s.Tried,
+		Solved:          s.Solved,
+		Contests:        s.Contests,
+		MeanTimeToACSec: int64(s.MeanTimeToAC() / time.Second),
+		Verdicts:        s.Verdicts,
+		Problems:        []ProblemStats{},
+	}
+	for _, p := range s.Problems {
+		ps := ProblemStats{Contest: p.Contest, Problem: p.Problem, URL: p.URL, Attempts: p.Attempts, Solved: p.Solved, AttemptsToAC: p.AttemptsToAC}
+		if p.Solved {
+			sec := int64(p.TimeToAC / time.Second)
+			ps.TimeToACSec = &sec
+		}
+		out.Problems = append(out.Problems, ps)
+	}
+	return out
+}
+
+type TeamActivity struct {
+	Problem string    `json:"problem"`
+	File    string    `json:"file,omitempty"`
+	Verdict string    `json:"verdict,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+type TeamMember struct {
+	Name    string        `json:"name"`
+	Claims  []string      `json:"claims"`
+	Last    *TeamActivity `json:"last,omitempty"`
+	Updated time.Time     `json:"updated"`
+}
+
+func FromTeam(members []team.Member) []TeamMember {
+	out := make([]TeamMember, 0, len(members))
+	for _, m := range members {
+		tm := TeamMember{Name: m.Name, Claims: []string{}, Updated: m.Updated}
+		for p := range m.Claims {
+			tm.Claims = append(tm.Claims, p)
+		}
+		sort.Strings(tm.Claims)
+		if m.Last != nil {
+			tm.Last = &TeamActivity{Problem: m.Last.Problem, File: m.Last.File, Verdict: m.Last.Verdict, Time: m.Last.Time}
+		}
+		out = append(out, tm)
+	}
+	return out
+}
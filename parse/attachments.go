@@ -0,0 +1,242 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type Attachment struct {
+	URL  string
+	Name string
+	Kind string
+	Path string
+}
+
+type Statement struct {
+	URL         string
+	Text        string
+	Attachments []Attachment
+	PDF         []byte
+
+	root *html.Node
+	base string
+}
+
+var attachmentExts = map[string]bool{
+	".pdf": true, ".zip": true, ".rar": true, ".7z": true, ".tar": true, ".gz": true, ".tgz": true,
+	".doc": true, ".docx": true, ".txt": true, ".in": true, ".out": true, ".ans": true,
+	".c": true, ".cpp": true, ".cc": true, ".h": true, ".py": true, ".java": true, ".pas": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".bmp": true,
+}
+
+func FetchStatement(client *http.Client, problemURL string) (*Statement, error) {
+	doc, err := fetchStatementDoc(client, problemURL)
+	if err != nil {
+		return nil, err
+	}
+	if doc.pdf != nil {
+		return &Statement{URL: problemURL, Text: pdfStatementText(doc), PDF: doc.pdf, base: doc.url}, nil
+	}
+	root, base := doc.root, doc.url
+	st := &Statement{URL: problemURL, root: root, base: base}
+	var buf bytes.Buffer
+	if err := extractTextWithFormulas(root, &buf, false); err != nil {
+		return nil, fmt.Errorf("extract text: %w", err)
+	}
+	st.Text = wrapLines(cleanExtracted(buf.String()), MaxLineWidth)
+	st.Attachments = findAttachments(root, base)
+	rewriteRefs(root, base, nil)
+	return st, nil
+}
+
+func (s *Statement) Markdown() (string, error) {
+	if s.root == nil {
+		return s.Text, nil
+	}
+	var buf bytes.Buffer
+	if err := extractTextWithFormulas(s.root, &buf, true); err != nil {
+		return "", fmt.Errorf("extract text: %w", err)
+	}
+	return wrapLines(cleanExtracted(buf.String()), MaxLineWidth), nil
+}
+
+func (s *Statement) HTML() (string, error) {
+	if s.root == nil {
+		return "", errors.New("statement is a PDF document, it has no HTML form")
+	}
+	var buf bytes.Buffer
+	if err := html.Render(&buf, s.root); err != nil {
+		return "", fmt.Errorf("render html: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (s *Statement) SaveAttachments(client *http.Client, dir, linkPrefix string) error {
+	if len(s.Attachments) == 0 {
+		return nil
+	}
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	local := map[string]string{}
+	used := map[string]bool{}
+	for i := range s.Attachments {
+		at := &s.Attachments[i]
+		name := at.Name
+		for n := 1; used[name]; n++ {
+			ext := path.Ext(at.Name)
+			name = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(at.Name, ext), n, ext)
+		}
+		used[name] = true
+		p, err := attachmentPath(dir, name)
+		if err != nil {
+			return err
+		}
+		at.Path = p
+		err = download(client, at.URL, at.Path)
+		if err != nil {
+			return err
+		}
+		local[at.URL] = path.Join(linkPrefix, url.PathEscape(name))
+	}
+	rewriteRefs(s.root, s.base, local)
+	return nil
+}
+
+func attachmentPath(dir, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("unsafe attachment name %q", name)
+	}
+	p := filepath.Join(dir, name)
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("attachment %q escapes %s", name, dir)
+	}
+	return p, nil
+}
+
+func download(client *http.Client, u, dst string) error {
+	resp, err := client.Get(u)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("GET %s returned %s", u, resp.Status)
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", dst, err)
+	}
+	defer f.Close()
+	_, err = io.Copy(f, resp.Body)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", dst, err)
+	}
+	return nil
+}
+
+func findAttachments(root *html.Node, base string) []Attachment {
+	var out []Attachment
+	seen := map[string]bool{}
+	add := func(ref, kind string) {
+		if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "mailto:") {
+			return
+		}
+		u := resolveRelativeURL(base, ref)
+		if seen[u] {
+			return
+		}
+		seen[u] = true
+		name := "file"
+		if pu, err := url.Parse(u); err == nil {
+			if b := path.Base(pu.Path); b != "/" && b != "." {
+				name = b
+			}
+		}
+		out = append(out, Attachment{URL: u, Name: name, Kind: kind})
+	}
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch strings.ToLower(n.Data) {
+			case "img":
+				add(attr(n, "src"), "image")
+			case "a":
+				if href := attr(n, "href"); isAttachmentLink(href) {
+					add(href, "file")
+				}
+			case "object":
+				add(attr(n, "data"), "file")
+			case "embed":
+				add(attr(n, "src"), "file")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+	return out
+}
+
+func rewriteRefs(root *html.Node, base string, local map[string]string) {
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			for i, a := range n.Attr {
+				if a.Key != "src" && a.Key != "href" && a.Key != "data" {
+					continue
+				}
+				if a.Val == "" || strings.HasPrefix(a.Val, "#") || strings.HasPrefix(a.Val, "data:") {
+					continue
+				}
+				abs := resolveRelativeURL(base, a.Val)
+				if p, ok := local[abs]; ok {
+					n.Attr[i].Val = p
+				} else {
+					n.Attr[i].Val = abs
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+}
+
+func isAttachmentLink(href string) bool {
+	if href == "" {
+		return false
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	p := strings.ToLower(u.Path)
+	if attachmentExts[path.Ext(p)] {
+		return true
+	}
+	return strings.Contains(p, "download") || strings.Contains(p, "attachment")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package parse
+
+import (
+	"net/http"
+	"sync"
+)
+
+type StatementResult struct {
+	Problem Problem
+	Text    string
+	Err     error
+}
+
+func FetchStatements(client *http.Client, problems []Problem, workers int, progress func(done, total int, r *StatementResult)) []StatementResult {
+	if workers <= 0 {
+		workers = 4
+	}
+	results := make([]StatementResult, len(problems))
+	jobs := make(chan int)
+	var mu sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	for range min(workers, len(problems)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				text, err := FetchStatementToString(client, problems[i].URL)
+				results[i] = StatementResult{Problem: problems[i], Text: text, Err: err}
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, len(problems), &results[i])
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range problems {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
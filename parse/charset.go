@@ -0,0 +1,58 @@
+package parse
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var reMetaCharset = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?([\w-]+)`)
+
+func DecodeHTML(b []byte, contentType string) []byte {
+	if utf8.Valid(b) {
+		return b
+	}
+	out, err := htmlEncoding(b, contentType).NewDecoder().Bytes(b)
+	if err != nil {
+		return b
+	}
+	return out
+}
+
+func DecodeBody(resp *http.Response) (io.Reader, error) {
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(DecodeHTML(b, resp.Header.Get("Content-Type"))), nil
+}
+
+func htmlEncoding(b []byte, contentType string) encoding.Encoding {
+	enc, name := charset.Lookup(declaredCharset(b, contentType))
+	if enc == nil || name == "utf-8" {
+		return charmap.Windows1251
+	}
+	return enc
+}
+
+func declaredCharset(b []byte, contentType string) string {
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return strings.ToLower(params["charset"])
+	}
+	head := b
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	if m := reMetaCharset.FindSubmatch(head); m != nil {
+		return strings.ToLower(string(m[1]))
+	}
+	return "windows-1251"
+}
@@ -0,0 +1,85 @@
+package parse
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// "Задача" in windows-1251 and "Ґанок, їжак, Євген, і" in koi8-u.
+const (
+	cp1251Task = "\xc7\xe0\xe4\xe0\xf7\xe0"
+	koi8uWords = "\xbd\xc1\xce\xcf\xcb, \xa7\xd6\xc1\xcb, \xb4\xd7\xc7\xc5\xce, \xa6"
+)
+
+func TestDecodeHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{
+		{
+			name:        "header wins over a disagreeing meta",
+			body:        `<html><head><meta charset="utf-8"></head><body>` + cp1251Task + `</body></html>`,
+			contentType: "text/html; charset=windows-1251",
+			want:        `<html><head><meta charset="utf-8"></head><body>Задача</body></html>`,
+		},
+		{
+			name: "meta without header",
+			body: `<meta http-equiv="Content-Type" content="text/html; charset=windows-1251"><p>` + cp1251Task,
+			want: `<meta http-equiv="Content-Type" content="text/html; charset=windows-1251"><p>Задача`,
+		},
+		{
+			name:        "header claims utf-8 for cp1251 bytes",
+			body:        `<p>` + cp1251Task,
+			contentType: "text/html; charset=utf-8",
+			want:        `<p>Задача`,
+		},
+		{
+			name: "no declaration defaults to cp1251",
+			body: cp1251Task,
+			want: "Задача",
+		},
+		{
+			name:        "koi8-u keeps Ukrainian letters",
+			body:        koi8uWords,
+			contentType: "text/html; charset=koi8-u",
+			want:        "Ґанок, їжак, Євген, і",
+		},
+		{
+			name:        "valid utf-8 is left alone",
+			body:        "<p>Задача",
+			contentType: "text/html; charset=windows-1251",
+			want:        "<p>Задача",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(DecodeHTML([]byte(tt.body), tt.contentType)); got != tt.want {
+				t.Errorf("DecodeHTML = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBodyParsesOnce(t *testing.T) {
+	page := `<html><head><meta charset="koi8-r"></head><body>` +
+		`<ul class="menu"><li><a href="/cs/problem/aid1pid1">A. ` + cp1251Task + `</a></li></ul></body></html>`
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": {"text/html; charset=windows-1251"}},
+		Body:   io.NopCloser(strings.NewReader(page)),
+	}
+	body, err := DecodeBody(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := ParseProblems(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Name != "A. Задача" {
+		t.Errorf("ParseProblems after DecodeBody = %+v, want one problem named Задача", problems)
+	}
+}
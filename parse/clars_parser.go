@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type Clarification struct {
+	ID       string
+	Time     time.Time
+	Problem  string
+	Question string
+	Answer   string
+	Answered bool
+}
+
+func ParseClarifications(r io.Reader) ([]Clarification, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	t := findTable(doc, "вопрос", "question", "сообщение", "message")
+	if t == nil {
+		return []Clarification{}, nil
+	}
+	var (
+		idCol       = t.col("#", "№", "id")
+		timeCol     = t.col("время", "time", "дата", "date")
+		problemCol  = t.col("задача", "problem")
+		questionCol = t.col("вопрос", "question", "сообщение", "message")
+		answerCol   = t.col("ответ", "answer", "reply")
+	)
+	var out []Clarification
+	for _, row := range t.rows {
+		c := Clarification{
+			Problem:  t.cell(row, problemCol),
+			Question: t.cell(row, questionCol),
+			Answer:   t.cell(row, answerCol),
+		}
+		if c.Question == "" {
+			continue
+		}
+		ts := t.cell(row, timeCol)
+		if tm, ok := parseServerTime(ts); ok {
+			c.Time = tm
+		}
+		c.Answered = c.Answer != "" && !isPending(c.Answer)
+		if !c.Answered {
+			c.Answer = ""
+		}
+		c.ID = t.cell(row, idCol)
+		if c.ID == "" {
+			sum := sha1.Sum([]byte(ts + "\n" + c.Problem + "\n" + c.Question))
+			c.ID = hex.EncodeToString(sum[:6])
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func isPending(answer string) bool {
+	a := strings.ToLower(strings.Trim(answer, " -—.()"))
+	switch a {
+	case "", "нет ответа", "ожидает ответа", "не отвечен", "no answer", "pending", "waiting":
+		return true
+	}
+	return false
+}
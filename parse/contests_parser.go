@@ -2,15 +2,35 @@ package parse
 
 import (
 	"io"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 	"golang.org/x/net/html"
 )
 
 type Contest struct {
 	Name string
 	URL string
+	Start time.Time
+	Duration time.Duration
+	End time.Time
+	Freeze time.Time
+	State ContestState
+	ServerOffset time.Duration
 }
 
+type ContestState string
+
+const (
+	StateUnknown  ContestState = ""
+	StateUpcoming ContestState = "upcoming"
+	StateRunning  ContestState = "running"
+	StateFrozen   ContestState = "frozen"
+	StateFinished ContestState = "finished"
+)
+
 func ParseContests(r io.Reader) ([]Contest, error) {
 	doc, err := html.Parse(r)
 	if err != nil {
@@ -36,10 +56,14 @@ func ParseContests(r io.Reader) ([]Contest, error) {
 			if href != "" && strings.Contains(href, "ranking-table") {
 				text := strings.TrimSpace(extractText(n))
 				if text != "" {
-					contests = append(contests, Contest{
+					c := Contest{
 						Name: text,
 						URL:  href,
-					})
+					}
+					if n.Parent != nil {
+						FillContestInfo(&c, extractText(n.Parent))
+					}
+					contests = append(contests, c)
 				}
 			}
 		}
@@ -88,3 +112,146 @@ func extractText(n *html.Node) string {
 	f(n)
 	return b.String()
 }
+
+var ServerLocation = time.FixedZone("MSK", 3*60*60)
+
+const reDateTime = `(\d{4}[-./]\d{2}[-./]\d{2}[ T]+\d{1,2}:\d{2}(?::\d{2})?|\d{2}\.\d{2}\.\d{4}[ ,]+\d{1,2}:\d{2}(?::\d{2})?)`
+
+var (
+	reContestStart    = regexp.MustCompile(`(?i)(?:начало|начинается|start(?:s|ed)?(?:\s+time)?)\s*(?:[а-яa-z]+\s*)?:?\s*` + reDateTime)
+	reContestEnd      = regexp.MustCompile(`(?i)(?:окончание|конец|заканчивается|end(?:s|ed)?(?:\s+time)?|finish(?:es)?)\s*(?:[а-яa-z]+\s*)?:?\s*` + reDateTime)
+	reContestDuration = regexp.MustCompile(`(?i)(?:продолжительность|длительность|duration)\s*:?\s*(?:(\d+:\d{2}(?::\d{2})?)|(\d+)\s*(ч|h|мин|min|m))`)
+	reServerTime      = regexp.MustCompile(`(?i)(?:серверное\s+время|время\s+сервера|текущее\s+время|server\s+time|current\s+time)\s*:?\s*` + reDateTime)
+	reFreezeAt        = regexp.MustCompile(`(?i)(?:заморозк\S*|freeze|frozen)[^0-9]{0,40}` + reDateTime)
+	reFreezeBefore    = regexp.MustCompile(`(?i)(?:заморозк\S*|freeze|frozen)[^0-9]{0,40}(\d+)\s*(ч|h|мин|min|m)`)
+	reStateFrozen     = regexp.MustCompile(`(?i)(таблица\s+заморожена|результаты\s+заморожены|standings\s+(?:are\s+)?frozen)`)
+	reStateFinished   = regexp.MustCompile(`(?i)(олимпиада\s+(?:завершена|окончена)|соревнование\s+(?:завершено|окончено)|contest\s+(?:is\s+)?(?:over|finished))`)
+	reStateUpcoming   = regexp.MustCompile(`(?i)(ещ[её]\s+не\s+начал\S*|not\s+(?:yet\s+)?started)`)
+	reStateRunning    = regexp.MustCompile(`(?i)(ид[её]т|in\s+progress|is\s+running)`)
+)
+
+func FillContestInfo(c *Contest, text string) {
+	if m := reContestStart.FindStringSubmatch(text); m != nil {
+		if t, ok := parseServerTime(m[1]); ok {
+			c.Start = t
+		}
+	}
+	if m := reContestEnd.FindStringSubmatch(text); m != nil {
+		if t, ok := parseServerTime(m[1]); ok {
+			c.End = t
+		}
+	}
+	if m := reContestDuration.FindStringSubmatch(text); m != nil {
+		if m[1] != "" {
+			c.Duration = parseClock(m[1])
+		} else {
+			c.Duration = parseAmount(m[2], m[3])
+		}
+	}
+	switch {
+	case c.End.IsZero() && !c.Start.IsZero() && c.Duration > 0:
+		c.End = c.Start.Add(c.Duration)
+	case c.Duration == 0 && !c.Start.IsZero() && !c.End.IsZero():
+		c.Duration = c.End.Sub(c.Start)
+	case c.Start.IsZero() && !c.End.IsZero() && c.Duration > 0:
+		c.Start = c.End.Add(-c.Duration)
+	}
+	if m := reFreezeAt.FindStringSubmatch(text); m != nil {
+		if t, ok := parseServerTime(m[1]); ok {
+			c.Freeze = t
+		}
+	} else if m := reFreezeBefore.FindStringSubmatch(text); m != nil && !c.End.IsZero() {
+		c.Freeze = c.End.Add(-parseAmount(m[1], m[2]))
+	}
+	switch {
+	case reStateFrozen.MatchString(text):
+		c.State = StateFrozen
+	case reStateFinished.MatchString(text):
+		c.State = StateFinished
+	case reStateUpcoming.MatchString(text):
+		c.State = StateUpcoming
+	case reStateRunning.MatchString(text):
+		c.State = StateRunning
+	}
+}
+
+func ParseContestPage(r io.Reader, c *Contest, received time.Time) error {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return err
+	}
+	text := extractText(doc)
+	FillContestInfo(c, text)
+	if m := reServerTime.FindStringSubmatch(text); m != nil {
+		if t, ok := parseServerTime(m[1]); ok {
+			c.ServerOffset = t.Sub(received).Round(time.Second)
+		}
+	}
+	return nil
+}
+
+func ServerOffsetFromHeader(h http.Header, received time.Time) (time.Duration, bool) {
+	t, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	return t.Sub(received).Round(time.Second), true
+}
+
+func (c *Contest) Now() time.Time {
+	return time.Now().Add(c.ServerOffset)
+}
+
+func (c *Contest) StateAt(t time.Time) ContestState {
+	switch {
+	case !c.End.IsZero() && !t.Before(c.End):
+		return StateFinished
+	case !c.Start.IsZero() && t.Before(c.Start):
+		return StateUpcoming
+	case !c.Freeze.IsZero() && !t.Before(c.Freeze) && (!c.Start.IsZero() || c.State != StateUpcoming):
+		return StateFrozen
+	case !c.Start.IsZero() && !c.End.IsZero():
+		return StateRunning
+	}
+	if c.State == StateUpcoming && !c.Start.IsZero() {
+		return StateRunning
+	}
+	return c.State
+}
+
+func parseServerTime(s string) (time.Time, bool) {
+	s = strings.Join(strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == 'T' }), " ")
+	layouts := []string{
+		"2006-01-02 15:04:05", "2006-01-02 15:04",
+		"2006/01/02 15:04:05", "2006/01/02 15:04",
+		"2006.01.02 15:04:05", "2006.01.02 15:04",
+		"02.01.2006 15:04:05", "02.01.2006 15:04",
+	}
+	for _, l := range layouts {
+		t, err := time.ParseInLocation(l, s, ServerLocation)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseClock(s string) time.Duration {
+	parts := strings.Split(s, ":")
+	var d time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for i, p := range parts {
+		n, _ := strconv.Atoi(p)
+		d += time.Duration(n) * units[i]
+	}
+	return d
+}
+
+func parseAmount(n, unit string) time.Duration {
+	v, _ := strconv.Atoi(n)
+	unit = strings.ToLower(unit)
+	if unit == "ч" || unit == "h" {
+		return time.Duration(v) * time.Hour
+	}
+	return time.Duration(v) * time.Minute
+}
@@ -0,0 +1,34 @@
+package parse
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStateAt(t *testing.T) {
+	start := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+	freeze := start.Add(4 * time.Hour)
+	end := start.Add(5 * time.Hour)
+	tests := []struct {
+		name string
+		c    Contest
+		at   time.Time
+		want ContestState
+	}{
+		{"before start", Contest{Start: start, End: end}, start.Add(-time.Minute), StateUpcoming},
+		{"running", Contest{Start: start, End: end, Freeze: freeze}, start.Add(time.Hour), StateRunning},
+		{"frozen", Contest{Start: start, End: end, Freeze: freeze}, freeze, StateFrozen},
+		{"at end", Contest{Start: start, End: end}, end, StateFinished},
+		{"only end, before it", Contest{End: end, State: StateRunning}, end.Add(-time.Minute), StateRunning},
+		{"only end, after it", Contest{End: end, State: StateRunning}, end.Add(time.Second), StateFinished},
+		{"only end, page said upcoming", Contest{End: end, State: StateUpcoming}, start, StateUpcoming},
+		{"only start, after it", Contest{Start: start, State: StateUpcoming}, start.Add(time.Minute), StateRunning},
+		{"only start, page said finished", Contest{Start: start, State: StateFinished}, start.Add(time.Minute), StateFinished},
+		{"no times", Contest{State: StateFrozen}, start, StateFrozen},
+	}
+	for _, tt := range tests {
+		if got := tt.c.StateAt(tt.at); got != tt.want {
+			t.Errorf("%s: StateAt = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package parse
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+type Announcement struct {
+	ID   string
+	Time time.Time
+	Text string
+}
+
+var reNewsDate = regexp.MustCompile(`^\[?(\d{4}[-./]\d{2}[-./]\d{2}(?:[ T,]+\d{1,2}:\d{2}(?::\d{2})?)?|\d{2}\.\d{2}\.\d{4}(?:[ ,]+\d{1,2}:\d{2}(?::\d{2})?)?)\]?\s*[:\-–—]?\s*`)
+
+func ParseMotd(r io.Reader) ([]Announcement, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	if menu := findMenu(doc); menu != nil && menu.Parent != nil {
+		menu.Parent.RemoveChild(menu)
+	}
+	removeElements(doc, "script", "style", "head")
+	root := findByClassOrID(doc, "motd", "news")
+	if root == nil {
+		root = doc
+	}
+	var buf bytes.Buffer
+	err = extractTextWithFormulas(root, &buf, false)
+	if err != nil {
+		return nil, err
+	}
+	var out []Announcement
+	var cur *Announcement
+	var lines []string
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(lines, "\n"))
+		lines = nil
+		if cur == nil && text == "" {
+			return
+		}
+		if cur == nil {
+			cur = &Announcement{}
+		}
+		cur.Text = text
+		sum := sha1.Sum([]byte(cur.Time.String() + "\n" + cur.Text))
+		cur.ID = hex.EncodeToString(sum[:6])
+		out = append(out, *cur)
+		cur = nil
+	}
+	for _, ln := range strings.Split(buf.String(), "\n") {
+		ln = strings.TrimSpace(ln)
+		if m := reNewsDate.FindStringSubmatch(ln); m != nil {
+			t, ok := parseServerTime(m[1])
+			if !ok {
+				t, ok = parseServerDate(m[1])
+			}
+			if ok {
+				flush()
+				cur = &Announcement{Time: t}
+				ln = strings.TrimSpace(ln[len(m[0]):])
+			}
+		}
+		if ln == "" && len(lines) > 0 && lines[len(lines)-1] == "" {
+			continue
+		}
+		if ln == "" && len(lines) == 0 {
+			continue
+		}
+		lines = append(lines, ln)
+	}
+	flush()
+	return out, nil
+}
+
+func parseServerDate(s string) (time.Time, bool) {
+	for _, l := range []string{"2006-01-02", "2006/01/02", "2006.01.02", "02.01.2006"} {
+		t, err := time.ParseInLocation(l, s, ServerLocation)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func removeElements(n *html.Node, names ...string) {
+	var next *html.Node
+	for c := n.FirstChild; c != nil; c = next {
+		next = c.NextSibling
+		if c.Type == html.ElementNode {
+			for _, name := range names {
+				if strings.EqualFold(c.Data, name) {
+					n.RemoveChild(c)
+					break
+				}
+			}
+			if c.Parent == nil {
+				continue
+			}
+		}
+		removeElements(c, names...)
+	}
+}
+
+func findByClassOrID(n *html.Node, keys ...string) *html.Node {
+	if n.Type == html.ElementNode {
+		for _, a := range n.Attr {
+			if a.Key != "class" && a.Key != "id" {
+				continue
+			}
+			v := strings.ToLower(a.Val)
+			for _, k := range keys {
+				if strings.Contains(v, k) {
+					return n
+				}
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if res := findByClassOrID(c, keys...); res != nil {
+			return res
+		}
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package parse
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	reObj      = regexp.MustCompile(`\d+\s+\d+\s+obj\b`)
+	reStreamKw = regexp.MustCompile(`>>\s*stream\r?\n`)
+	reLength   = regexp.MustCompile(`/Length\s+(\d+)(\s+\d+\s+R)?`)
+	reBfChar   = regexp.MustCompile(`(?s)beginbfchar(.*?)endbfchar`)
+	reBfRange  = regexp.MustCompile(`(?s)beginbfrange(.*?)endbfrange`)
+	reHexTok   = regexp.MustCompile(`<([0-9A-Fa-f]+)>|\[([^\]]*)\]`)
+)
+
+func pdfStreams(data []byte) [][]byte {
+	var streams [][]byte
+	for pos := 0; pos < len(data); {
+		loc := reObj.FindIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		head := pos + loc[1]
+		pos = head
+		limit := len(data)
+		if n := bytes.Index(data[head:], []byte("endobj")); n >= 0 {
+			limit = head + n
+		}
+		body := data[head:limit]
+		open := bytes.Index(body, []byte("<<"))
+		if open < 0 || len(bytes.TrimSpace(body[:open])) > 0 {
+			continue
+		}
+		kw := reStreamKw.FindIndex(body)
+		if kw == nil {
+			continue
+		}
+		dict := string(body[open+2 : kw[0]])
+		start := head + kw[1]
+		var raw []byte
+		if m := reLength.FindStringSubmatch(dict); m != nil && m[2] == "" {
+			n, err := strconv.Atoi(m[1])
+			if err == nil && start+n <= len(data) {
+				raw = data[start : start+n]
+			}
+		}
+		if raw == nil {
+			end := bytes.Index(data[start:], []byte("endstream"))
+			if end < 0 {
+				continue
+			}
+			raw = bytes.TrimRight(data[start:start+end], "\r\n")
+		}
+		pos = start + len(raw)
+		switch {
+		case strings.Contains(dict, "/FlateDecode"):
+			r, err := zlib.NewReader(bytes.NewReader(raw))
+			if err != nil {
+				continue
+			}
+			b, _ := io.ReadAll(r)
+			if len(b) > 0 {
+				streams = append(streams, b)
+			}
+		case strings.Contains(dict, "/Filter"):
+		default:
+			streams = append(streams, raw)
+		}
+	}
+	return streams
+}
+
+func ExtractPDFText(data []byte) string {
+	streams := pdfStreams(data)
+	cmap := map[string]string{}
+	for _, s := range streams {
+		if bytes.Contains(s, []byte("begincmap")) {
+			parseToUnicode(string(s), cmap)
+		}
+	}
+	var out strings.Builder
+	for _, s := range streams {
+		if bytes.Contains(s, []byte("begincmap")) || !bytes.Contains(s, []byte("BT")) {
+			continue
+		}
+		pdfContentText(s, cmap, &out)
+		out.WriteString("\n")
+	}
+	var lines []string
+	for _, ln := range strings.Split(out.String(), "\n") {
+		ln = strings.Join(strings.Fields(ln), " ")
+		if ln != "" || (len(lines) > 0 && lines[len(lines)-1] != "") {
+			lines = append(lines, ln)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func parseToUnicode(s string, cmap map[string]string) {
+	for _, m := range reBfChar.FindAllStringSubmatch(s, -1) {
+		toks := reHexTok.FindAllStringSubmatch(m[1], -1)
+		for i := 0; i+1 < len(toks); i += 2 {
+			cmap[strings.ToUpper(toks[i][1])] = utf16Hex(toks[i+1][1])
+		}
+	}
+	for _, m := range reBfRange.FindAllStringSubmatch(s, -1) {
+		toks := reHexTok.FindAllStringSubmatch(m[1], -1)
+		for i := 0; i+2 < len(toks); i += 3 {
+			lo, err1 := strconv.ParseUint(toks[i][1], 16, 32)
+			hi, err2 := strconv.ParseUint(toks[i+1][1], 16, 32)
+			if err1 != nil || err2 != nil || hi < lo || hi-lo > 0xffff {
+				continue
+			}
+			width := len(toks[i][1])
+			if toks[i+2][2] != "" {
+				dsts := reHexTok.FindAllStringSubmatch(toks[i+2][2], -1)
+				for j, d := range dsts {
+					cmap[hexCode(lo+uint64(j), width)] = utf16Hex(d[1])
+				}
+				continue
+			}
+			base := []rune(utf16Hex(toks[i+2][1]))
+			if len(base) == 0 {
+				continue
+			}
+			for c := lo; c <= hi; c++ {
+				r := append([]rune{}, base...)
+				r[len(r)-1] += rune(c - lo)
+				cmap[hexCode(c, width)] = string(r)
+			}
+		}
+	}
+}
+
+func hexCode(c uint64, width int) string {
+	s := strings.ToUpper(strconv.FormatUint(c, 16))
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
+
+func utf16Hex(h string) string {
+	var units []uint16
+	for i := 0; i+4 <= len(h); i += 4 {
+		v, err := strconv.ParseUint(h[i:i+4], 16, 16)
+		if err != nil {
+			return ""
+		}
+		units = append(units, uint16(v))
+	}
+	if len(h) == 2 {
+		v, _ := strconv.ParseUint(h, 16, 8)
+		units = append(units, uint16(v))
+	}
+	var r []rune
+	for i := 0; i < len(units); i++ {
+		u := rune(units[i])
+		if u >= 0xd800 && u < 0xdc00 && i+1 < len(units) {
+			u = (u-0xd800)<<10 + (rune(units[i+1]) - 0xdc00) + 0x10000
+			i++
+		}
+		r = append(r, u)
+	}
+	return string(r)
+}
+
+type pdfOperand struct {
+	str   []byte
+	hex   bool
+	num   float64
+	isNum bool
+	arr   []pdfOperand
+	isArr bool
+}
+
+func pdfContentText(s []byte, cmap map[string]string, out *strings.Builder) {
+	var stack []pdfOperand
+	var arr *[]pdfOperand
+	push := func(o pdfOperand) {
+		if arr != nil {
+			*arr = append(*arr, o)
+		} else {
+			stack = append(stack, o)
+		}
+	}
+	lastY, haveY := 0.0, false
+	moveTo := func(y float64) {
+		if haveY && y != lastY {
+			out.WriteString("\n")
+		} else {
+			out.WriteString(" ")
+		}
+		lastY, haveY = y, true
+	}
+	num := func(i int) float64 {
+		if i >= 0 && i < len(stack) && stack[i].isNum {
+			return stack[i].num
+		}
+		return 0
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0:
+			i++
+		case c == '%':
+			for i < len(s) && s[i] != '\n' && s[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			str, n := pdfLiteral(s[i:])
+			push(pdfOperand{str: str})
+			i += n
+		case c == '<' && i+1 < len(s) && s[i+1] == '<':
+			i += 2
+		case c == '>' && i+1 < len(s) && s[i+1] == '>':
+			i += 2
+		case c == '<':
+			end := bytes.IndexByte(s[i:], '>')
+			if end < 0 {
+				return
+			}
+			push(pdfOperand{str: s[i+1 : i+end], hex: true})
+			i += end + 1
+		case c == '[':
+			stack = append(stack, pdfOperand{isArr: true})
+			arr = &stack[len(stack)-1].arr
+			i++
+		case c == ']':
+			arr = nil
+			i++
+		default:
+			j := i + 1
+			for j < len(s) && !bytes.ContainsRune([]byte(" \n\r\t\f()<>[]/%"), rune(s[j])) {
+				j++
+			}
+			tok := string(s[i:j])
+			i = j
+			if v, err := strconv.ParseFloat(tok, 64); err == nil {
+				push(pdfOperand{num: v, isNum: true})
+				continue
+			}
+			if strings.HasPrefix(tok, "/") {
+				push(pdfOperand{})
+				continue
+			}
+			switch tok {
+			case "Tj":
+				if len(stack) > 0 {
+					out.WriteString(pdfDecode(stack[len(stack)-1], cmap))
+				}
+			case "'", "\"":
+				out.WriteString("\n")
+				if len(stack) > 0 {
+					out.WriteString(pdfDecode(stack[len(stack)-1], cmap))
+				}
+			case "TJ":
+				if len(stack) > 0 {
+					for _, o := range stack[len(stack)-1].arr {
+						if o.isNum {
+							if o.num < -200 {
+								out.WriteString(" ")
+							}
+							continue
+						}
+						out.WriteString(pdfDecode(o, cmap))
+					}
+				}
+			case "T*":
+				out.WriteString("\n")
+			case "Td", "TD":
+				if num(1) != 0 {
+					out.WriteString("\n")
+				} else {
+					out.WriteString(" ")
+				}
+			case "Tm":
+				moveTo(num(5))
+			case "ET":
+				out.WriteString(" ")
+			}
+			stack = stack[:0]
+			arr = nil
+		}
+	}
+}
+
+func pdfLiteral(s []byte) ([]byte, int) {
+	var out []byte
+	depth := 0
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '(':
+			if depth > 0 {
+				out = append(out, c)
+			}
+			depth++
+			i++
+		case c == ')':
+			depth--
+			i++
+			if depth == 0 {
+				return out, i
+			}
+			out = append(out, c)
+		case c == '\\' && i+1 < len(s):
+			e := s[i+1]
+			i += 2
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && i < len(s) && s[i] >= '0' && s[i] <= '7'; k++ {
+						v = v*8 + int(s[i]-'0')
+						i++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+			i++
+		}
+	}
+	return out, i
+}
+
+func pdfDecode(o pdfOperand, cmap map[string]string) string {
+	var code []byte
+	if o.hex {
+		h := strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.ASCII_Hex_Digit, r) {
+				return unicode.ToUpper(r)
+			}
+			return -1
+		}, string(o.str))
+		if len(h)%2 == 1 {
+			h += "0"
+		}
+		for i := 0; i+2 <= len(h); i += 2 {
+			v, _ := strconv.ParseUint(h[i:i+2], 16, 8)
+			code = append(code, byte(v))
+		}
+	} else {
+		code = o.str
+	}
+	var b strings.Builder
+	for i := 0; i < len(code); {
+		if i+1 < len(code) {
+			if u, ok := cmap[hexCode(uint64(code[i])<<8|uint64(code[i+1]), 4)]; ok {
+				b.WriteString(u)
+				i += 2
+				continue
+			}
+		}
+		if u, ok := cmap[hexCode(uint64(code[i]), 2)]; ok {
+			b.WriteString(u)
+		} else if r := rune(code[i]); unicode.IsPrint(r) {
+			b.WriteRune(r)
+		}
+		i++
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package parse
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func testPDF(objs ...string) []byte {
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	for i, o := range objs {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return []byte(b.String())
+}
+
+func rawStream(data string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(data), data)
+}
+
+func flateStream(data string) string {
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write([]byte(data))
+	w.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.String())
+}
+
+const testCMap = `/CIDInit /ProcSet findresource begin
+begincmap
+2 beginbfchar
+<0001> <0041>
+<0002> <0020>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0430>
+endbfrange
+1 beginbfrange
+<0020> <0021> [<0058> <0059>]
+endbfrange
+endcmap`
+
+func TestExtractPDFText(t *testing.T) {
+	tests := []struct {
+		name string
+		pdf  []byte
+		want string
+	}{
+		{
+			name: "raw stream",
+			pdf:  testPDF("<< /Type /Catalog >>", rawStream("BT /F1 12 Tf 72 700 Td (Hello world) Tj ET")),
+			want: "Hello world",
+		},
+		{
+			name: "flate stream",
+			pdf:  testPDF(flateStream("BT /F1 12 Tf 72 700 Td (Compressed text) Tj ET")),
+			want: "Compressed text",
+		},
+		{
+			name: "direct length cuts a stream containing endstream",
+			pdf:  testPDF(rawStream("BT (a endstream b) Tj ET")),
+			want: "a endstream b",
+		},
+		{
+			name: "indirect length falls back to endstream",
+			pdf:  testPDF("<< /Length 2 0 R >>\nstream\nBT (Indirect) Tj ET\nendstream", "20"),
+			want: "Indirect",
+		},
+		{
+			name: "dictionary without stream does not swallow the next object",
+			pdf: testPDF(
+				"<< /Type /Page /Contents 2 0 R >>",
+				rawStream("BT (First) Tj ET"),
+				"<< /Type /Font /Subtype /Type1 >>",
+				flateStream("BT (Second) Tj ET"),
+			),
+			want: "First\nSecond",
+		},
+		{
+			name: "unsupported filter is skipped",
+			pdf:  testPDF("<< /Length 9 /Filter /DCTDecode >>\nstream\nBT (x) Tj\nendstream", rawStream("BT (Kept) Tj ET")),
+			want: "Kept",
+		},
+		{
+			name: "ToUnicode bfchar and bfrange",
+			pdf:  testPDF(flateStream(testCMap), rawStream("BT <0001000200100011001200020020 0021> Tj ET")),
+			want: "A абв XY",
+		},
+		{
+			name: "TJ array with kerning numbers",
+			pdf:  testPDF(rawStream("BT [(Hel) -20 (lo) -300 (wor) 15 (ld)] TJ ET")),
+			want: "Hello world",
+		},
+		{
+			name: "text matrix moves start new lines",
+			pdf:  testPDF(rawStream("BT 1 0 0 1 72 700 Tm (Line one) Tj 1 0 0 1 72 680 Tm (Line two) Tj ET")),
+			want: "Line one\nLine two",
+		},
+		{
+			name: "escapes in literal strings",
+			pdf:  testPDF(rawStream(`BT (a \(b\) \101\102) Tj ET`)),
+			want: "a (b) AB",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractPDFText(tt.pdf); got != tt.want {
+				t.Errorf("ExtractPDFText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFStreams(t *testing.T) {
+	data := testPDF(
+		"<< /Type /Catalog >>",
+		rawStream("first"),
+		"<< /Length 3 0 R >>\nstream\nsecond\nendstream",
+		flateStream("third"),
+	)
+	var got []string
+	for _, s := range pdfStreams(data) {
+		got = append(got, string(s))
+	}
+	want := []string{"first", "second", "third"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("pdfStreams = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+package parse
+
+import (
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type Sample struct {
+	Input  string
+	Output string
+}
+
+func FetchSamples(client *http.Client, problemURL string) ([]Sample, error) {
+	doc, err := fetchStatementDoc(client, problemURL)
+	if err != nil {
+		return nil, err
+	}
+	if doc.pdf != nil {
+		return nil, nil
+	}
+	return ParseSamples(doc.root), nil
+}
+
+func ParseSamples(root *html.Node) []Sample {
+	if s := samplesFromTables(root); len(s) > 0 {
+		return s
+	}
+	return samplesFromPre(root)
+}
+
+func samplesFromTables(root *html.Node) []Sample {
+	var out []Sample
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && strings.EqualFold(n.Data, "table") {
+			rows := tableRows(n)
+			if len(rows) > 1 && len(rows[0]) >= 2 && isInputLabel(extractText(rows[0][0])) && isOutputLabel(extractText(rows[0][1])) {
+				for _, r := range rows[1:] {
+					if len(r) >= 2 {
+						out = append(out, Sample{Input: preText(r[0]), Output: preText(r[1])})
+					}
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+	return out
+}
+
+func tableRows(t *html.Node) [][]*html.Node {
+	var rows [][]*html.Node
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch strings.ToLower(c.Data) {
+			case "thead", "tbody", "tfoot":
+				f(c)
+			case "tr":
+				var cells []*html.Node
+				for td := c.FirstChild; td != nil; td = td.NextSibling {
+					if td.Type == html.ElementNode && (td.Data == "td" || td.Data == "th") {
+						cells = append(cells, td)
+					}
+				}
+				rows = append(rows, cells)
+			}
+		}
+	}
+	f(t)
+	return rows
+}
+
+func samplesFromPre(root *html.Node) []Sample {
+	type block struct {
+		label string
+		text  string
+	}
+	var blocks []block
+	label := ""
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			switch t := n.Data; {
+			case isInputLabel(t):
+				label = "in"
+			case isOutputLabel(t):
+				label = "out"
+			}
+			return
+		}
+		if n.Type == html.ElementNode && strings.EqualFold(n.Data, "pre") {
+			blocks = append(blocks, block{label, preText(n)})
+			label = ""
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(root)
+	var out []Sample
+	for i := 0; i+1 < len(blocks); i++ {
+		a, b := blocks[i], blocks[i+1]
+		if a.label == "out" || b.label == "in" {
+			continue
+		}
+		out = append(out, Sample{Input: a.text, Output: b.text})
+		i++
+	}
+	return out
+}
+
+func isInputLabel(s string) bool {
+	s = strings.ToLower(s)
+	return strings.Contains(s, "вход") || strings.Contains(s, "ввод") || strings.Contains(s, "input")
+}
+
+func isOutputLabel(s string) bool {
+	s = strings.ToLower(s)
+	return strings.Contains(s, "выход") || strings.Contains(s, "вывод") || strings.Contains(s, "output")
+}
+
+func preText(n *html.Node) string {
+	var b strings.Builder
+	var f func(*html.Node)
+	f = func(x *html.Node) {
+		if x.Type == html.TextNode {
+			b.WriteString(x.Data)
+		}
+		if x.Type == html.ElementNode && strings.EqualFold(x.Data, "br") {
+			b.WriteByte('\n')
+		}
+		for c := x.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	s := strings.ReplaceAll(b.String(), "\r\n", "\n")
+	return strings.Trim(s, "\n") + "\n"
+}
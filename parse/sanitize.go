@@ -0,0 +1,80 @@
+package parse
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+func sanitizeWordHTML(n *html.Node) {
+	var next *html.Node
+	for c := n.FirstChild; c != nil; c = next {
+		next = c.NextSibling
+		switch c.Type {
+		case html.CommentNode:
+			n.RemoveChild(c)
+			continue
+		case html.ElementNode:
+			name := strings.ToLower(c.Data)
+			switch {
+			case name == "style", name == "xml", name == "meta", name == "link":
+				n.RemoveChild(c)
+				continue
+			case name == "script" && !strings.Contains(strings.ToLower(attr(c, "type")), "math"):
+				n.RemoveChild(c)
+				continue
+			case strings.HasPrefix(name, "o:"), strings.HasPrefix(name, "v:"):
+				n.RemoveChild(c)
+				continue
+			case strings.Contains(name, ":"):
+				sanitizeWordHTML(c)
+				if strings.TrimSpace(extractAllText(c)) == "" {
+					n.RemoveChild(c)
+					continue
+				}
+				for gc := c.FirstChild; gc != nil; gc = c.FirstChild {
+					c.RemoveChild(gc)
+					n.InsertBefore(gc, c)
+				}
+				n.RemoveChild(c)
+				continue
+			}
+			c.Attr = cleanWordAttrs(c.Attr)
+		}
+		sanitizeWordHTML(c)
+	}
+}
+
+func cleanWordAttrs(attrs []html.Attribute) []html.Attribute {
+	out := attrs[:0]
+	for _, a := range attrs {
+		switch strings.ToLower(a.Key) {
+		case "class":
+			var keep []string
+			for _, c := range strings.Fields(a.Val) {
+				lc := strings.ToLower(c)
+				if !strings.HasPrefix(lc, "mso") {
+					keep = append(keep, c)
+				}
+			}
+			if len(keep) == 0 {
+				continue
+			}
+			a.Val = strings.Join(keep, " ")
+		case "style":
+			var keep []string
+			for _, d := range strings.Split(a.Val, ";") {
+				d = strings.TrimSpace(d)
+				if d != "" && !strings.HasPrefix(strings.ToLower(d), "mso-") {
+					keep = append(keep, d)
+				}
+			}
+			if len(keep) == 0 {
+				continue
+			}
+			a.Val = strings.Join(keep, "; ")
+		}
+		out = append(out, a)
+	}
+	return out
+}
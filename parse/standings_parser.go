@@ -0,0 +1,137 @@
+package parse
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type Standings struct {
+	Problems []string
+	Rows     []StandingsRow
+}
+
+type StandingsRow struct {
+	Place   int
+	Team    string
+	Solved  int
+	Penalty int
+	Score   int
+	Results map[string]ProblemResult
+}
+
+type ProblemResult struct {
+	Solved   bool
+	Attempts int
+	Time     int
+	Score    int
+}
+
+var (
+	reProblemCol = regexp.MustCompile(`^[A-Za-zА-Яа-я]\d?$|^\d{1,2}$`)
+	reCellICPC   = regexp.MustCompile(`^([+\-])(\d*)(?:\s*\(?(\d+):(\d{2})\)?)?`)
+	reCellNum    = regexp.MustCompile(`^(\d+)`)
+)
+
+func ParseStandings(r io.Reader) (*Standings, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	st := &Standings{}
+	t := findTable(doc, "место", "place", "rank")
+	if t == nil {
+		t = findTable(doc, "участник", "команда", "team", "contestant")
+	}
+	if t == nil {
+		return st, nil
+	}
+	var (
+		placeCol   = t.col("место", "place", "rank", "#", "№")
+		teamCol    = t.col("участник", "команда", "team", "contestant", "name", "user", "имя")
+		solvedCol  = t.col("решено", "solved", "задач", "=")
+		penaltyCol = t.col("штраф", "penalty", "время", "time")
+		scoreCol   = t.col("балл", "очки", "score", "points", "сумма", "total")
+	)
+	probCols := map[int]string{}
+	for i, h := range t.header {
+		if i == placeCol || i == teamCol || i == solvedCol || i == penaltyCol || i == scoreCol {
+			continue
+		}
+		if reProblemCol.MatchString(h) {
+			name := strings.ToUpper(h)
+			probCols[i] = name
+			st.Problems = append(st.Problems, name)
+		}
+	}
+	for n, row := range t.rows {
+		team := t.cell(row, teamCol)
+		if team == "" {
+			continue
+		}
+		sr := StandingsRow{Team: team, Results: map[string]ProblemResult{}}
+		if m := reCellNum.FindStringSubmatch(t.cell(row, placeCol)); m != nil {
+			sr.Place, _ = strconv.Atoi(m[1])
+		} else {
+			sr.Place = n + 1
+		}
+		sr.Penalty, _ = strconv.Atoi(t.cell(row, penaltyCol))
+		sr.Score, _ = strconv.Atoi(t.cell(row, scoreCol))
+		for i, name := range probCols {
+			res, ok := parseResultCell(t.cell(row, i))
+			if ok {
+				sr.Results[name] = res
+				if res.Solved {
+					sr.Solved++
+				}
+			}
+		}
+		if solvedCol >= 0 {
+			if v, err := strconv.Atoi(t.cell(row, solvedCol)); err == nil {
+				sr.Solved = v
+			}
+		}
+		st.Rows = append(st.Rows, sr)
+	}
+	return st, nil
+}
+
+func parseResultCell(s string) (ProblemResult, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "." || s == "-" {
+		return ProblemResult{}, false
+	}
+	if m := reCellICPC.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		res := ProblemResult{}
+		if m[1] == "+" {
+			res.Solved = true
+			res.Attempts = n + 1
+		} else {
+			res.Attempts = n
+		}
+		if m[3] != "" {
+			h, _ := strconv.Atoi(m[3])
+			mm, _ := strconv.Atoi(m[4])
+			res.Time = h*60 + mm
+		}
+		return res, true
+	}
+	if m := reCellNum.FindStringSubmatch(s); m != nil {
+		v, _ := strconv.Atoi(m[1])
+		return ProblemResult{Score: v, Attempts: 1}, true
+	}
+	return ProblemResult{}, false
+}
+
+func (s *Standings) Find(team string) *StandingsRow {
+	for i := range s.Rows {
+		if strings.EqualFold(s.Rows[i].Team, team) {
+			return &s.Rows[i]
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"regexp"
 	"strings"
 
@@ -15,48 +16,80 @@ import (
 const MaxLineWidth = 180
 
 func FetchStatementToString(client *http.Client, problemURL string) (string, error) {
+	doc, err := fetchStatementDoc(client, problemURL)
+	if err != nil {
+		return "", err
+	}
+	if doc.pdf != nil {
+		return pdfStatementText(doc), nil
+	}
+	var buf bytes.Buffer
+	if err := extractTextWithFormulas(doc.root, &buf, false); err != nil {
+		return "", fmt.Errorf("extract text: %w", err)
+	}
+	cleaned := cleanExtracted(buf.String())
+	out := wrapLines(cleaned, MaxLineWidth)
+	return out, nil
+}
+
+type statementDoc struct {
+	root *html.Node
+	url  string
+	pdf  []byte
+}
+
+func fetchStatementDoc(client *http.Client, problemURL string) (*statementDoc, error) {
 	if client == nil {
-		return "", fmt.Errorf("nil http client")
+		return nil, fmt.Errorf("nil http client")
+	}
+	doc, err := fetchDoc(client, problemURL)
+	if err != nil || doc.pdf != nil {
+		return doc, err
 	}
-	resp, err := client.Get(problemURL)
+	iframeSrc, ok := findIframeSrcPrefer(doc.root)
+	if !ok {
+		return doc, nil
+	}
+	return fetchDoc(client, resolveRelativeURL(problemURL, iframeSrc))
+}
+
+func fetchDoc(client *http.Client, u string) (*statementDoc, error) {
+	resp, err := client.Get(u)
 	if err != nil {
-		return "", fmt.Errorf("GET %s: %w", problemURL, err)
+		return nil, fmt.Errorf("GET %s: %w", u, err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 400 {
-		return "", fmt.Errorf("GET %s returned %s", problemURL, resp.Status)
+		return nil, fmt.Errorf("GET %s returned %s", u, resp.Status)
 	}
-	root, err := html.Parse(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("parse %s: %w", problemURL, err)
-	}
-	iframeSrc, ok := findIframeSrcPrefer(root)
-	var contentRoot *html.Node
-	if ok {
-		iframeURL := resolveRelativeURL(problemURL, iframeSrc)
-		resp2, err := client.Get(iframeURL)
-		if err != nil {
-			return "", fmt.Errorf("GET %s: %w", iframeURL, err)
-		}
-		defer resp2.Body.Close()
-		if resp2.StatusCode >= 400 {
-			return "", fmt.Errorf("GET %s returned %s", iframeURL, resp2.Status)
-		}
-		root2, err := html.Parse(resp2.Body)
-		if err != nil {
-			return "", fmt.Errorf("parse %s: %w", iframeURL, err)
-		}
-		contentRoot = root2
-	} else {
-		contentRoot = root
+		return nil, fmt.Errorf("read %s: %w", u, err)
 	}
-	var buf bytes.Buffer
-	if err := extractTextWithFormulas(contentRoot, &buf); err != nil {
-		return "", fmt.Errorf("extract text: %w", err)
+	if isPDF(resp.Header.Get("Content-Type"), body) {
+		return &statementDoc{url: u, pdf: body}, nil
 	}
-	cleaned := cleanExtracted(buf.String())
-	out := wrapLines(cleaned, MaxLineWidth)
-	return out, nil
+	root, err := html.Parse(bytes.NewReader(DecodeHTML(body, resp.Header.Get("Content-Type"))))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", u, err)
+	}
+	sanitizeWordHTML(root)
+	return &statementDoc{root: root, url: u}, nil
+}
+
+func isPDF(contentType string, body []byte) bool {
+	if strings.HasPrefix(strings.ToLower(contentType), "application/pdf") {
+		return true
+	}
+	return http.DetectContentType(body) == "application/pdf"
+}
+
+func pdfStatementText(doc *statementDoc) string {
+	text := strings.TrimSpace(ExtractPDFText(doc.pdf))
+	if text == "" {
+		return fmt.Sprintf("[PDF statement, %d bytes: %s]", len(doc.pdf), doc.url)
+	}
+	return wrapLines(text, MaxLineWidth)
 }
 
 func findIframeSrcPrefer(n *html.Node) (string, bool) {
@@ -127,7 +160,7 @@ func resolveRelativeURL(base, href string) string {
 	return baseu.ResolveReference(ref).String()
 }
 
-func extractTextWithFormulas(root *html.Node, w io.Writer) error {
+func extractTextWithFormulas(root *html.Node, w io.Writer, markdown bool) error {
 	if root == nil {
 		return nil
 	}
@@ -180,12 +213,24 @@ func extractTextWithFormulas(root *html.Node, w io.Writer) error {
 						alt = a.Val
 					}
 				}
-				if alt != "" {
+				if markdown {
+					appendInline(fmt.Sprintf("![%s](%s)", alt, attr(n, "src")))
+				} else if alt != "" {
 					appendInline(alt)
 				} else {
 					appendInline("[IMAGE]")
 				}
 				return nil
+			case "a":
+				href := attr(n, "href")
+				if markdown && isAttachmentLink(href) {
+					text := strings.TrimSpace(extractText(n))
+					if text == "" {
+						text = path.Base(href)
+					}
+					appendInline(fmt.Sprintf("[%s](%s)", text, href))
+					return nil
+				}
 			case "script":
 				var typ string
 				for _, a := range n.Attr {
@@ -287,7 +332,7 @@ func cleanExtracted(s string) string {
 	s = reComment.ReplaceAllString(s, "")
 	lines := strings.Split(s, "\n")
 	var out []string
-	reTrashLine := regexp.MustCompile(`(?i)^\s*(none|html|<[^>]+>|\s*/\*.*|\*.*\*/|@page|font-family|Font Definitions|\{|\}).*`)
+	reTrashLine := regexp.MustCompile(`(?i)^\s*(<[^>]+>|@page|font-family|Font Definitions).*`)
 	for i := range len(lines) {
 		ln := strings.TrimSpace(lines[i])
 		if ln == "" {
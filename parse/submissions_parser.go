@@ -0,0 +1,87 @@
+package parse
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type Submission struct {
+	ID       string
+	Time     time.Time
+	Problem  string
+	Language string
+	Verdict  string
+	Score    int
+	Test     int
+}
+
+func ParseSubmissions(r io.Reader) ([]Submission, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	t := findTable(doc, "вердикт", "verdict", "результат", "result", "статус", "status")
+	if t == nil {
+		return []Submission{}, nil
+	}
+	var (
+		idCol      = t.col("#", "№", "id", "посылка", "run")
+		timeCol    = t.col("время", "time", "дата", "date")
+		problemCol = t.col("задача", "problem")
+		langCol    = t.col("язык", "компилятор", "lang", "compiler")
+		verdictCol = t.col("вердикт", "verdict", "результат", "result", "статус", "status")
+		scoreCol   = t.col("балл", "очки", "score", "points")
+		testCol    = t.col("тест", "test")
+	)
+	var out []Submission
+	for _, row := range t.rows {
+		s := Submission{
+			ID:       t.cell(row, idCol),
+			Problem:  t.cell(row, problemCol),
+			Language: t.cell(row, langCol),
+			Verdict:  t.cell(row, verdictCol),
+		}
+		if s.Verdict == "" && s.ID == "" {
+			continue
+		}
+		if tm, ok := parseServerTime(t.cell(row, timeCol)); ok {
+			s.Time = tm
+		}
+		s.Score, _ = strconv.Atoi(t.cell(row, scoreCol))
+		s.Test, _ = strconv.Atoi(t.cell(row, testCol))
+		if s.ID == "" {
+			s.ID = t.cell(row, timeCol) + "|" + s.Problem
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (s *Submission) Pending() bool {
+	v := strings.ToLower(s.Verdict)
+	if v == "" {
+		return true
+	}
+	if strings.Contains(v, "ошибк") || strings.Contains(v, "error") {
+		return false
+	}
+	for _, p := range []string{"очеред", "компил", "тестир", "провер", "ожида", "queue", "compiling", "running", "testing", "judging", "waiting", "pending"} {
+		if strings.Contains(v, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Submission) Accepted() bool {
+	v := strings.ToLower(strings.TrimSpace(s.Verdict))
+	switch v {
+	case "ok", "ac", "accepted", "зачтено", "принято", "полное решение", "решено":
+		return true
+	}
+	return strings.HasPrefix(v, "ok ") || strings.HasPrefix(v, "accepted")
+}
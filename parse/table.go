@@ -0,0 +1,61 @@
+package parse
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type table struct {
+	header []string
+	rows   [][]*goquery.Selection
+}
+
+func findTable(doc *goquery.Document, keys ...string) *table {
+	var res *table
+	doc.Find("table").EachWithBreak(func(i int, t *goquery.Selection) bool {
+		var tb table
+		t.Find("tr").Each(func(j int, tr *goquery.Selection) {
+			if tr.Closest("table").Get(0) != t.Get(0) {
+				return
+			}
+			if tb.header == nil {
+				tr.ChildrenFiltered("th, td").Each(func(k int, c *goquery.Selection) {
+					tb.header = append(tb.header, strings.ToLower(strings.TrimSpace(c.Text())))
+				})
+				return
+			}
+			var row []*goquery.Selection
+			tr.ChildrenFiltered("th, td").Each(func(k int, c *goquery.Selection) {
+				row = append(row, c)
+			})
+			if len(row) > 0 {
+				tb.rows = append(tb.rows, row)
+			}
+		})
+		if tb.col(keys...) >= 0 {
+			res = &tb
+			return false
+		}
+		return true
+	})
+	return res
+}
+
+func (t *table) col(keys ...string) int {
+	for _, k := range keys {
+		for i, h := range t.header {
+			if strings.Contains(h, k) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func (t *table) cell(row []*goquery.Selection, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.Join(strings.Fields(row[i].Text()), " ")
+}
@@ -2,15 +2,40 @@ package parse
 
 import (
 	"io"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
 	"github.com/PuerkitoBio/goquery"
 )
 
 type Problem struct {
-	Name string
-	URL string
+	Name        string
+	URL         string
+	Short       string
+	TimeLimit   time.Duration
+	MemoryLimit int64
+	Input       string
+	Output      string
+	Solved      bool
+	Attempts    int
+	Score       int
+	MaxScore    int
 }
 
+var (
+	reShort   = regexp.MustCompile(`^\s*([A-Za-zА-Яа-я]\d{0,2}|\d{1,3})\s*[.):\-]\s+\S`)
+	reICPC    = regexp.MustCompile(`(?:^|\s|\()([+\-])(\d*)\)?\s*$`)
+	reScore   = regexp.MustCompile(`\(?\s*(\d+)\s*/\s*(\d+)\s*\)?\s*$`)
+	reTries   = regexp.MustCompile(`(?i)(\d+)\s*(?:попыт\S*|tries|attempts?)`)
+	reTime    = regexp.MustCompile(`(?i)(?:ограничение\s+(?:по\s+)?времени|time\s+limit)\s*(?:на\s+тест)?\s*:?\s*(\d+(?:[.,]\d+)?)\s*(мс|ms|миллисекунд\S*|s\b|сек\S*|second\S*)?`)
+	reMemory  = regexp.MustCompile(`(?i)(?:ограничение\s+(?:по\s+)?памяти|memory\s+limit)\s*(?:на\s+тест)?\s*:?\s*(\d+(?:[.,]\d+)?)\s*(кб|kb|кило\S*|kilo\S*|мб|mb|мега\S*|mega\S*|гб|gb|гига\S*|giga\S*|байт\S*|bytes?)?`)
+	reInput   = regexp.MustCompile(`(?i)(?:входной\s+файл|имя\s+входного\s+файла|ввод|input\s+file|input)\s*:\s*(стандартный\s+ввод|standard\s+input|stdin|[\w\-]+\.\w+)`)
+	reOutput  = regexp.MustCompile(`(?i)(?:выходной\s+файл|имя\s+выходного\s+файла|вывод|output\s+file|output)\s*:\s*(стандартный\s+вывод|standard\s+output|stdout|[\w\-]+\.\w+)`)
+	reMaxBall = regexp.MustCompile(`(?i)(?:максимальный\s+балл|max(?:imum)?\s+score)\s*:?\s*(\d+)`)
+)
+
 func ParseProblems(r io.Reader) ([]Problem, error) {
 	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
@@ -24,8 +49,90 @@ func ParseProblems(r io.Reader) ([]Problem, error) {
 		}
 		name := strings.TrimSpace(s.Text())
 		if name != "" {
-			problems = append(problems, Problem{ Name: name, URL: href, })
+			p := Problem{Name: name, URL: href}
+			parseMenuEntry(&p, s)
+			problems = append(problems, p)
 		}
 	})
 	return problems, nil
 }
+
+func parseMenuEntry(p *Problem, s *goquery.Selection) {
+	if m := reShort.FindStringSubmatch(p.Name); m != nil {
+		p.Short = strings.ToUpper(m[1])
+	} else {
+		u := strings.TrimRight(p.URL, "/")
+		p.Short = u[strings.LastIndexAny(u, "/=")+1:]
+	}
+	classes := strings.ToLower(s.AttrOr("class", "") + " " + s.Parent().AttrOr("class", ""))
+	for _, c := range []string{"solved", "accepted", "success", "ok"} {
+		if strings.Contains(" "+classes+" ", " "+c+" ") {
+			p.Solved = true
+		}
+	}
+	text := strings.TrimSpace(s.Parent().Text())
+	if m := reScore.FindStringSubmatch(text); m != nil {
+		p.Score, _ = strconv.Atoi(m[1])
+		p.MaxScore, _ = strconv.Atoi(m[2])
+		if p.MaxScore > 0 && p.Score == p.MaxScore {
+			p.Solved = true
+		}
+	} else if m := reICPC.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "+" {
+			p.Solved = true
+			p.Attempts = n + 1
+		} else {
+			p.Attempts = n
+		}
+	}
+	if m := reTries.FindStringSubmatch(text); m != nil {
+		p.Attempts, _ = strconv.Atoi(m[1])
+	}
+}
+
+func FillFromStatement(p *Problem, statement string) {
+	if m := reTime.FindStringSubmatch(statement); m != nil {
+		v, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+		if err == nil {
+			unit := strings.ToLower(m[2])
+			if strings.HasPrefix(unit, "м") || unit == "ms" {
+				p.TimeLimit = time.Duration(v * float64(time.Millisecond))
+			} else {
+				p.TimeLimit = time.Duration(v * float64(time.Second))
+			}
+		}
+	}
+	if m := reMemory.FindStringSubmatch(statement); m != nil {
+		v, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+		if err == nil {
+			unit := strings.ToLower(m[2])
+			mul := float64(1 << 20)
+			switch {
+			case strings.HasPrefix(unit, "к"), strings.HasPrefix(unit, "k"):
+				mul = 1 << 10
+			case strings.HasPrefix(unit, "г"), strings.HasPrefix(unit, "g"):
+				mul = 1 << 30
+			case strings.HasPrefix(unit, "б"), strings.HasPrefix(unit, "b"):
+				mul = 1
+			}
+			p.MemoryLimit = int64(v * mul)
+		}
+	}
+	if m := reInput.FindStringSubmatch(statement); m != nil {
+		p.Input = fileName(m[1])
+	}
+	if m := reOutput.FindStringSubmatch(statement); m != nil {
+		p.Output = fileName(m[1])
+	}
+	if m := reMaxBall.FindStringSubmatch(statement); m != nil {
+		p.MaxScore, _ = strconv.Atoi(m[1])
+	}
+}
+
+func fileName(s string) string {
+	if strings.Contains(s, ".") {
+		return s
+	}
+	return ""
+}
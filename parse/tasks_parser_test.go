@@ -0,0 +1,85 @@
+package parse
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMenu = `<html><body><ul class="menu">
+<li class="solved"><a href="/cs/problem/aid1pid1">A. Сумма</a> +</li>
+<li><a href="/cs/problem/aid1pid2">B) Разность</a> -2</li>
+<li><a href="/cs/problem/aid1pid3">C1 - Partial</a> (30/100)</li>
+<li><a href="/cs/problem/aid1pid4">Ж. Буква</a> 3 попытки</li>
+<li><a href="/cs/problem?id=7">Untitled</a></li>
+<li><a href="/cs/problem/aid1pid6">12. Numbered</a> +1</li>
+</ul>
+<a href="/cs/problem/aid1pid9">outside the menu</a>
+</body></html>`
+
+func TestParseProblems(t *testing.T) {
+	problems, err := ParseProblems(strings.NewReader(testMenu))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Problem{
+		{Name: "A. Сумма", URL: "/cs/problem/aid1pid1", Short: "A", Solved: true, Attempts: 1},
+		{Name: "B) Разность", URL: "/cs/problem/aid1pid2", Short: "B", Attempts: 2},
+		{Name: "C1 - Partial", URL: "/cs/problem/aid1pid3", Short: "C1", Score: 30, MaxScore: 100},
+		{Name: "Ж. Буква", URL: "/cs/problem/aid1pid4", Short: "Ж", Attempts: 3},
+		{Name: "Untitled", URL: "/cs/problem?id=7", Short: "7"},
+		{Name: "12. Numbered", URL: "/cs/problem/aid1pid6", Short: "12", Solved: true, Attempts: 2},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("got %d problems, want %d: %+v", len(problems), len(want), problems)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problem %d:\n got %+v\nwant %+v", i, problems[i], want[i])
+		}
+	}
+}
+
+func TestFillFromStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      Problem
+	}{
+		{
+			name: "russian",
+			statement: "Задача A. Сумма\nОграничение времени: 2 секунды\nОграничение памяти: 256 мегабайт\n" +
+				"Входной файл: input.txt\nВыходной файл: стандартный вывод\nМаксимальный балл: 100\n",
+			want: Problem{TimeLimit: 2 * time.Second, MemoryLimit: 256 << 20, Input: "input.txt", MaxScore: 100},
+		},
+		{
+			name:      "russian per-test limits in other units",
+			statement: "Ограничение по времени на тест: 1,5\nОграничение по памяти на тест: 65536 КБ\nВвод: sum.in\nВывод: sum.out\n",
+			want:      Problem{TimeLimit: 1500 * time.Millisecond, MemoryLimit: 64 << 20, Input: "sum.in", Output: "sum.out"},
+		},
+		{
+			name:      "english",
+			statement: "Problem B\nTime limit: 500 ms\nMemory limit: 64 MB\nInput: standard input\nOutput file: output.txt\nMaximum score: 40\n",
+			want:      Problem{TimeLimit: 500 * time.Millisecond, MemoryLimit: 64 << 20, Output: "output.txt", MaxScore: 40},
+		},
+		{
+			name:      "english gigabytes",
+			statement: "Time Limit: 1 second\nMemory Limit: 1 GB\n",
+			want:      Problem{TimeLimit: time.Second, MemoryLimit: 1 << 30},
+		},
+		{
+			name:      "missing limits",
+			statement: "Даны два числа a и b. Выведите их сумму.\n",
+			want:      Problem{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Problem
+			FillFromStatement(&p, tt.statement)
+			if p != tt.want {
+				t.Errorf("FillFromStatement:\n got %+v\nwant %+v", p, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const DefaultServer = "http://server.aesc.msu.ru"
+
+type Profile struct {
+	Name      string   `json:"name"`
+	Server    string   `json:"server"`
+	Logpass   string   `json:"logpass"`
+	Cookies   string   `json:"cookies"`
+	Dir       string   `json:"dir"`
+	Contest   string   `json:"contest,omitempty"`
+	Member    string   `json:"member,omitempty"`
+	Team      string   `json:"team,omitempty"`
+	TeamToken string   `json:"team_token,omitempty"`
+	TeamName  string   `json:"team_name,omitempty"`
+	Notify    []string `json:"notify,omitempty"`
+}
+
+type Config struct {
+	Current  string    `json:"current"`
+	Profiles []Profile `json:"profiles"`
+
+	root string
+}
+
+func DefaultRoot() (string, error) {
+	if dir := os.Getenv("AESC_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get homedir: %w", err)
+	}
+	return filepath.Join(home, ".aesc"), nil
+}
+
+func Load(root string) (*Config, error) {
+	cfg := &Config{root: root}
+	b, err := os.ReadFile(filepath.Join(root, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Save() error {
+	err := os.MkdirAll(c.root, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", c.root, err)
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	path := filepath.Join(c.root, "config.json")
+	err = os.WriteFile(path, append(b, '\n'), 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) Root() string {
+	return c.root
+}
+
+func (c *Config) Add(name, server, logpass string) (*Profile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid profile name %q", name)
+	}
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return nil, fmt.Errorf("profile %q already exists", name)
+		}
+	}
+	if server == "" {
+		server = DefaultServer
+	}
+	dir := filepath.Join(c.root, "profiles", name)
+	if logpass == "" {
+		logpass = filepath.Join(dir, "login")
+	}
+	c.Profiles = append(c.Profiles, Profile{
+		Name:    name,
+		Server:  strings.TrimRight(server, "/"),
+		Logpass: logpass,
+		Cookies: filepath.Join(dir, "cookies"),
+		Dir:     dir,
+	})
+	sort.Slice(c.Profiles, func(i, j int) bool { return c.Profiles[i].Name < c.Profiles[j].Name })
+	if c.Current == "" {
+		c.Current = name
+	}
+	return c.Get(name)
+}
+
+func (c *Config) Get(name string) (*Profile, error) {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no such profile %q", name)
+}
+
+func (c *Config) Use(name string) error {
+	_, err := c.Get(name)
+	if err != nil {
+		return err
+	}
+	c.Current = name
+	return nil
+}
+
+func (c *Config) Resolve(name string) (*Profile, error) {
+	if name == "" {
+		name = c.Current
+	}
+	if name != "" {
+		return c.Get(name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get homedir: %w", err)
+	}
+	dir := filepath.Join(c.root, "profiles", "default")
+	return &Profile{
+		Name:    "default",
+		Server:  DefaultServer,
+		Logpass: filepath.Join(home, ".aesc_login"),
+		Cookies: filepath.Join(dir, "cookies"),
+		Dir:     dir,
+	}, nil
+}
+
+func (p *Profile) URL(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return p.Server + path
+}
+
+func (p *Profile) Path(elem ...string) string {
+	return filepath.Join(append([]string{p.Dir}, elem...)...)
+}
@@ -0,0 +1,146 @@
+package standings
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+type metric struct {
+	title  string
+	value  func(Point) int
+	invert bool
+}
+
+var metrics = []metric{
+	{"place", func(p Point) int { return p.Place }, true},
+	{"solved", func(p Point) int { return p.Solved }, false},
+}
+
+func (s Series) at(t time.Time) Point {
+	p := s.Points[0]
+	for _, q := range s.Points {
+		if q.Time.After(t) {
+			break
+		}
+		p = q
+	}
+	return p
+}
+
+func (s Series) span() (time.Time, time.Time) {
+	return s.Points[0].Time, s.Points[len(s.Points)-1].Time
+}
+
+func bounds(pts []Point, value func(Point) int) (int, int) {
+	lo, hi := value(pts[0]), value(pts[0])
+	for _, p := range pts {
+		lo, hi = min(lo, value(p)), max(hi, value(p))
+	}
+	if lo == hi {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+func (s Series) ASCII(width, height int) string {
+	if len(s.Points) == 0 {
+		return ""
+	}
+	width, height = max(width, 2), max(height, 2)
+	t0, t1 := s.span()
+	var b strings.Builder
+	for _, m := range metrics {
+		lo, hi := bounds(s.Points, m.value)
+		top, bottom := hi, lo
+		if m.invert {
+			top, bottom = lo, hi
+		}
+		label := max(len(fmt.Sprint(top)), len(fmt.Sprint(bottom)))
+		grid := make([][]byte, height)
+		for i := range grid {
+			grid[i] = []byte(strings.Repeat(" ", width))
+		}
+		for c := 0; c < width; c++ {
+			t := t0.Add(t1.Sub(t0) * time.Duration(c) / time.Duration(width-1))
+			row := (m.value(s.at(t)) - lo) * (height - 1) / (hi - lo)
+			if !m.invert {
+				row = height - 1 - row
+			}
+			grid[row][c] = '*'
+		}
+		fmt.Fprintf(&b, "%s of %s\n", m.title, s.Team)
+		for i, line := range grid {
+			num := ""
+			switch i {
+			case 0:
+				num = fmt.Sprint(top)
+			case height - 1:
+				num = fmt.Sprint(bottom)
+			}
+			fmt.Fprintf(&b, "%*s |%s\n", label, num, line)
+		}
+		start, end := t0.Local().Format("15:04"), t1.Local().Format("15:04")
+		fmt.Fprintf(&b, "%*s +%s\n", label, "", strings.Repeat("-", width))
+		fmt.Fprintf(&b, "%*s  %s%*s\n\n", label, "", start, max(width-len(start), len(end)+1), end)
+	}
+	return b.String()
+}
+
+func (s Series) SVG() string {
+	const (
+		w, panel, left, right, gap = 720, 180, 50, 20, 40
+	)
+	var b strings.Builder
+	h := gap + len(metrics)*(panel+gap)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", w, h)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", w, h)
+	if len(s.Points) == 0 {
+		b.WriteString("</svg>\n")
+		return b.String()
+	}
+	t0, t1 := s.span()
+	total := t1.Sub(t0)
+	x := func(t time.Time) float64 {
+		if total <= 0 {
+			return left
+		}
+		return left + float64(w-left-right)*float64(t.Sub(t0))/float64(total)
+	}
+	for i, m := range metrics {
+		y0 := gap + i*(panel+gap)
+		lo, hi := bounds(s.Points, m.value)
+		y := func(v int) float64 {
+			f := float64(v-lo) / float64(hi-lo)
+			if !m.invert {
+				f = 1 - f
+			}
+			return float64(y0) + f*panel
+		}
+		top, bottom := hi, lo
+		if m.invert {
+			top, bottom = lo, hi
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-weight="bold">%s of %s</text>`+"\n", left, y0-10, m.title, html.EscapeString(s.Team))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#999"/>`+"\n", left, y0, w-left-right, panel)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", left-6, y0+12, top)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", left-6, y0+panel, bottom)
+		fmt.Fprintf(&b, `<text x="%d" y="%d">%s</text>`+"\n", left, y0+panel+16, t0.Local().Format("15:04"))
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", w-right, y0+panel+16, t1.Local().Format("15:04"))
+		var pts []string
+		prev := -1
+		for _, p := range s.Points {
+			v := m.value(p)
+			if prev >= 0 {
+				pts = append(pts, fmt.Sprintf("%.1f,%.1f", x(p.Time), y(prev)))
+			}
+			pts = append(pts, fmt.Sprintf("%.1f,%.1f", x(p.Time), y(v)))
+			prev = v
+		}
+		pts = append(pts, fmt.Sprintf("%.1f,%.1f", float64(w-right), y(prev)))
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#1f6feb" stroke-width="2"/>`+"\n", strings.Join(pts, " "))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package standings
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"aesc-client/parse"
+)
+
+type Snapshot struct {
+	Time      time.Time        `json:"time"`
+	Standings *parse.Standings `json:"standings"`
+}
+
+type Store struct {
+	path  string
+	snaps []Snapshot
+	last  []byte
+}
+
+func Open(dir, contest string) (*Store, error) {
+	sum := sha1.Sum([]byte(contest))
+	s := &Store{path: filepath.Join(dir, hex.EncodeToString(sum[:10])+".jsonl")}
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", s.path, err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(sc.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, n, err)
+		}
+		if snap.Standings == nil {
+			continue
+		}
+		s.snaps = append(s.snaps, snap)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", s.path, err)
+	}
+	if len(s.snaps) > 0 {
+		s.last, _ = json.Marshal(s.snaps[len(s.snaps)-1].Standings)
+	}
+	return s, nil
+}
+
+func (s *Store) Snapshots() []Snapshot {
+	return s.snaps
+}
+
+func (s *Store) Record(st *parse.Standings, t time.Time) (bool, error) {
+	if len(st.Rows) == 0 {
+		return false, nil
+	}
+	cur, err := json.Marshal(st)
+	if err != nil {
+		return false, fmt.Errorf("encode standings: %w", err)
+	}
+	if bytes.Equal(cur, s.last) {
+		return false, nil
+	}
+	b, err := json.Marshal(Snapshot{Time: t, Standings: st})
+	if err != nil {
+		return false, fmt.Errorf("encode standings: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return false, fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return false, fmt.Errorf("open %s: %w", s.path, err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		return false, fmt.Errorf("write %s: %w", s.path, err)
+	}
+	s.snaps = append(s.snaps, Snapshot{Time: t, Standings: st})
+	s.last = cur
+	return true, nil
+}
+
+type Point struct {
+	Time   time.Time
+	Place  int
+	Solved int
+}
+
+type Series struct {
+	Team   string
+	Points []Point
+}
+
+func (s *Store) Series(team string) Series {
+	out := Series{Team: team}
+	for _, snap := range s.snaps {
+		r := snap.Standings.Find(team)
+		if r == nil {
+			continue
+		}
+		out.Team = r.Team
+		out.Points = append(out.Points, Point{Time: snap.Time, Place: r.Place, Solved: r.Solved})
+	}
+	return out
+}
+
+func (s *Store) Teams() []string {
+	if len(s.snaps) == 0 {
+		return nil
+	}
+	var names []string
+	for _, r := range s.snaps[len(s.snaps)-1].Standings.Rows {
+		names = append(names, strings.TrimSpace(r.Team))
+	}
+	return names
+}
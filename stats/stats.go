@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"aesc-client/submit"
+)
+
+type Problem struct {
+	Contest      string
+	Problem      string
+	URL          string
+	Attempts     int
+	Solved       bool
+	AttemptsToAC int
+	TimeToAC     time.Duration
+	First        time.Time
+}
+
+type Summary struct {
+	Submissions int
+	Tried       int
+	Solved      int
+	Contests    int
+	Verdicts    map[string]int
+	Problems    []Problem
+}
+
+func Compute(entries []submit.Entry) Summary {
+	s := Summary{Verdicts: map[string]int{}}
+	sorted := append([]submit.Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	byURL := map[string]int{}
+	contests := map[string]bool{}
+	for _, e := range sorted {
+		s.Submissions++
+		v := e.Verdict
+		if !e.Final() {
+			v = "pending"
+		}
+		s.Verdicts[v]++
+		contests[e.Contest] = true
+		i, ok := byURL[e.URL]
+		if !ok {
+			i = len(s.Problems)
+			byURL[e.URL] = i
+			s.Problems = append(s.Problems, Problem{Contest: e.Contest, Problem: e.Problem, URL: e.URL, First: e.Time})
+		}
+		p := &s.Problems[i]
+		p.Attempts++
+		if e.Accepted && !p.Solved {
+			p.Solved = true
+			p.AttemptsToAC = p.Attempts
+			p.TimeToAC = e.Time.Sub(p.First)
+		}
+	}
+	s.Tried = len(s.Problems)
+	s.Contests = len(contests)
+	for _, p := range s.Problems {
+		if p.Solved {
+			s.Solved++
+		}
+	}
+	sort.SliceStable(s.Problems, func(i, j int) bool {
+		if s.Problems[i].Contest != s.Problems[j].Contest {
+			return s.Problems[i].Contest < s.Problems[j].Contest
+		}
+		return s.Problems[i].Problem < s.Problems[j].Problem
+	})
+	return s
+}
+
+func (s Summary) MeanTimeToAC() time.Duration {
+	var total time.Duration
+	n := 0
+	for _, p := range s.Problems {
+		if p.Solved {
+			total += p.TimeToAC
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return total / time.Duration(n)
+}
@@ -0,0 +1,120 @@
+package submit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const DefaultMaxSize = 64 << 10
+
+var (
+	ErrRefused      = errors.New("submission refused")
+	ErrNotConfirmed = errors.New("submission not confirmed")
+)
+
+type Level int
+
+const (
+	Warn Level = iota
+	Confirm
+	Refuse
+)
+
+type Issue struct {
+	Level   Level
+	Message string
+}
+
+type Checker struct {
+	MaxSize int
+	Problem string
+	Key     string
+	FileIO  bool
+	History *Log
+	Force   bool
+	Confirm func(question string) bool
+	Warn    func(message string)
+}
+
+var (
+	reFreopen     = regexp.MustCompile(`\bfreopen\s*\(`)
+	reDebugDefine = regexp.MustCompile(`(?m)^\s*#\s*define\s+(DEBUG|LOCAL|_DEBUG)\b`)
+	reStderr      = regexp.MustCompile(`\bcerr\s*<<|\bfprintf\s*\(\s*stderr\b|\bsys\.stderr\b|\bSystem\.err\b|\bConsole\.Error\b`)
+	reLineComment = regexp.MustCompile(`(?m)^\s*//.*$`)
+	rePyComment   = regexp.MustCompile(`(?m)^\s*#.*$`)
+	reFileLetter  = regexp.MustCompile(`^([A-Za-z])\d?(?:[_\-.]|$)`)
+	reLatinLetter = regexp.MustCompile(`^[A-Za-z]$`)
+)
+
+func Hash(src []byte) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Checker) Check(name string, src []byte) []Issue {
+	var issues []Issue
+	add := func(l Level, format string, args ...any) {
+		issues = append(issues, Issue{Level: l, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(bytes.TrimSpace(src)) == 0 {
+		add(Refuse, "%s is empty", name)
+		return issues
+	}
+	limit := c.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	if len(src) > limit {
+		add(Warn, "%s is %d bytes, the server accepts at most %d", name, len(src), limit)
+	}
+	code := reLineComment.ReplaceAll(src, nil)
+	if strings.EqualFold(filepath.Ext(name), ".py") {
+		code = rePyComment.ReplaceAll(code, nil)
+	}
+	if !c.FileIO && reFreopen.Match(code) {
+		add(Warn, "%s calls freopen but the problem uses standard input/output", name)
+	}
+	if m := reDebugDefine.FindSubmatch(code); m != nil {
+		add(Warn, "%s still has #define %s", name, m[1])
+	}
+	if n := len(reStderr.FindAll(code, -1)); n >= 3 {
+		add(Warn, "%s writes to stderr in %d places, leftover debug output slows it down", name, n)
+	}
+	if c.History != nil {
+		if prev, ok := c.History.Find(c.Key, Hash(src)); ok {
+			add(Confirm, "identical source was already submitted as %s at %s", prev.File, prev.Time.Format("15:04:05"))
+		}
+	}
+	stem := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	if m := reFileLetter.FindStringSubmatch(stem); m != nil && reLatinLetter.MatchString(c.Problem) && !strings.EqualFold(m[1], c.Problem) {
+		add(Confirm, "file %s looks like problem %s, but you are submitting to %s", filepath.Base(name), strings.ToUpper(m[1]), c.Problem)
+	}
+	return issues
+}
+
+func (c *Checker) Run(name string, src []byte) error {
+	for _, is := range c.Check(name, src) {
+		switch is.Level {
+		case Refuse:
+			return fmt.Errorf("%w: %s", ErrRefused, is.Message)
+		case Confirm:
+			if c.Force {
+				continue
+			}
+			if c.Confirm == nil || !c.Confirm(is.Message) {
+				return fmt.Errorf("%w: %s", ErrNotConfirmed, is.Message)
+			}
+		default:
+			if c.Warn != nil {
+				c.Warn(is.Message)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package submit
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aesc-client/parse"
+)
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	history, err := OpenLog(filepath.Join(dir, "submissions.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := []byte("print(sum(map(int, input().split())))\n")
+	err = history.Append(NewEntry("c1", &parse.Problem{Short: "A", URL: "/problem/a"}, "a.py", "", sent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const ok = "int main() { return 0; }\n"
+	tests := []struct {
+		name    string
+		checker Checker
+		file    string
+		src     string
+		want    []Level
+	}{
+		{"clean", Checker{Problem: "A"}, "a.cpp", ok, nil},
+		{"empty", Checker{Problem: "A"}, "a.cpp", " \n\t\n", []Level{Refuse}},
+		{"too large", Checker{MaxSize: 10}, "a.cpp", ok, []Level{Warn}},
+		{"freopen on stdio problem", Checker{}, "a.cpp", "int main() { freopen(\"in.txt\", \"r\", stdin); }", []Level{Warn}},
+		{"freopen on file problem", Checker{FileIO: true}, "a.cpp", "int main() { freopen(\"in.txt\", \"r\", stdin); }", nil},
+		{"freopen commented out", Checker{}, "a.cpp", "// freopen(\"in.txt\", \"r\", stdin);\n" + ok, nil},
+		{"debug define", Checker{}, "a.cpp", "#define DEBUG\n" + ok, []Level{Warn}},
+		{"stderr spam", Checker{}, "a.cpp", "cerr << 1; cerr << 2; cerr << 3;", []Level{Warn}},
+		{"two stderr writes", Checker{}, "a.cpp", "cerr << 1; cerr << 2;", nil},
+		{"duplicate", Checker{Key: "/problem/a", History: history}, "a.py", string(sent), []Level{Confirm}},
+		{"same source, other problem", Checker{Key: "/problem/b", History: history}, "a.py", string(sent), nil},
+		{"file letter mismatch", Checker{Problem: "A"}, "b.cpp", ok, []Level{Confirm}},
+		{"file letter with digit", Checker{Problem: "A"}, "a2.cpp", ok, nil},
+		{"file letter case", Checker{Problem: "b"}, "B_fast.cpp", ok, nil},
+		{"numeric problem", Checker{Problem: "1"}, "a.cpp", ok, nil},
+		{"cyrillic problem", Checker{Problem: "Ж"}, "a.cpp", ok, nil},
+		{"multi-character problem", Checker{Problem: "C1"}, "c.cpp", ok, nil},
+		{"file name is not a letter", Checker{Problem: "A"}, "solution.cpp", ok, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Level
+			for _, is := range tt.checker.Check(tt.file, []byte(tt.src)) {
+				got = append(got, is.Level)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Check = %v, want %v", tt.checker.Check(tt.file, []byte(tt.src)), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issue %d level = %d, want %d", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	mismatch := func(c Checker) error {
+		return c.Run("b.cpp", []byte("int main() {}\n"))
+	}
+	if err := (&Checker{}).Run("a.cpp", nil); !errors.Is(err, ErrRefused) {
+		t.Errorf("empty source: got %v, want ErrRefused", err)
+	}
+	if err := mismatch(Checker{Problem: "A"}); !errors.Is(err, ErrNotConfirmed) {
+		t.Errorf("no confirm callback: got %v, want ErrNotConfirmed", err)
+	}
+	if err := mismatch(Checker{Problem: "A", Confirm: func(string) bool { return false }}); !errors.Is(err, ErrNotConfirmed) {
+		t.Errorf("declined: got %v, want ErrNotConfirmed", err)
+	}
+	var asked string
+	if err := mismatch(Checker{Problem: "A", Confirm: func(q string) bool { asked = q; return true }}); err != nil || !strings.Contains(asked, "problem B") {
+		t.Errorf("accepted: got %v after asking %q", err, asked)
+	}
+	if err := mismatch(Checker{Problem: "A", Force: true}); err != nil {
+		t.Errorf("forced: got %v", err)
+	}
+	var warnings []string
+	c := Checker{MaxSize: 4, Warn: func(w string) { warnings = append(warnings, w) }}
+	if err := c.Run("a.cpp", []byte("int main() {}\n")); err != nil || len(warnings) != 1 {
+		t.Errorf("warning: got %v, warnings %q", err, warnings)
+	}
+}
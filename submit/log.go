@@ -0,0 +1,147 @@
+package submit
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"aesc-client/parse"
+)
+
+type Entry struct {
+	ID           string     `json:"id"`
+	Time         time.Time  `json:"time"`
+	Contest      string     `json:"contest,omitempty"`
+	Problem      string     `json:"problem"`
+	URL          string     `json:"url"`
+	File         string     `json:"file"`
+	Hash         string     `json:"hash"`
+	Language     string     `json:"language"`
+	Member       string     `json:"member,omitempty"`
+	SubmissionID string     `json:"submission_id,omitempty"`
+	Verdict      string     `json:"verdict,omitempty"`
+	Accepted     bool       `json:"accepted,omitempty"`
+	Test         int        `json:"test,omitempty"`
+	Score        int        `json:"score,omitempty"`
+	Judged       *time.Time `json:"judged,omitempty"`
+	Virtual      string     `json:"virtual,omitempty"`
+	VirtualSec   int64      `json:"virtual_sec,omitempty"`
+}
+
+func (e *Entry) Final() bool {
+	return e.Judged != nil
+}
+
+func (e *Entry) SetVerdict(s *parse.Submission) {
+	if e.SubmissionID == "" {
+		e.SubmissionID = s.ID
+	}
+	if s.Pending() {
+		return
+	}
+	now := time.Now()
+	e.Verdict, e.Accepted, e.Test, e.Score, e.Judged = s.Verdict, s.Accepted(), s.Test, s.Score, &now
+}
+
+type Log struct {
+	path    string
+	entries []Entry
+	index   map[string]int
+}
+
+func OpenLog(path string) (*Log, error) {
+	l := &Log{path: path, index: map[string]int{}}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		l.put(e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return l, nil
+}
+
+func (l *Log) put(e Entry) {
+	if i, ok := l.index[e.ID]; ok {
+		l.entries[i] = e
+		return
+	}
+	l.index[e.ID] = len(l.entries)
+	l.entries = append(l.entries, e)
+}
+
+func (l *Log) Entries() []Entry {
+	return l.entries
+}
+
+func (l *Log) Find(url, hash string) (Entry, bool) {
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if l.entries[i].URL == url && l.entries[i].Hash == hash {
+			return l.entries[i], true
+		}
+	}
+	return Entry{}, false
+}
+
+func NewEntry(contest string, p *parse.Problem, file, lang string, src []byte) Entry {
+	if lang == "" {
+		lang = DetectLanguage(file)
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	return Entry{
+		ID:       hex.EncodeToString(id[:]),
+		Time:     time.Now(),
+		Contest:  contest,
+		Problem:  p.Short,
+		URL:      p.URL,
+		File:     filepath.Base(file),
+		Hash:     Hash(src),
+		Language: lang,
+	}
+}
+
+func (l *Log) Append(e Entry) error {
+	dir := filepath.Dir(l.path)
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode log entry: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", l.path, err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	if err != nil {
+		return fmt.Errorf("write %s: %w", l.path, err)
+	}
+	l.put(e)
+	return nil
+}
@@ -11,7 +11,7 @@ import (
 	"strings"
 )
 
-func detectLanguage(filePath string) string {
+func DetectLanguage(filePath string) string {
 	ext := strings.ToLower(filepath.Ext(filePath))
 	switch ext {
 	case ".cpp", ".cc", ".cxx":
@@ -33,23 +33,39 @@ func detectLanguage(filePath string) string {
 	}
 }
 
-func SubmitSolution(client *http.Client, actionURL, filePath string) error {
-	lang := detectLanguage(filePath)
-
+func SubmitSolution(client *http.Client, actionURL, filePath string, c *Checker) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
+	return Submit(client, actionURL, file, filepath.Base(filePath), "", c)
+}
+
+func Submit(client *http.Client, actionURL string, src io.Reader, filename, lang string, c *Checker) error {
+	code, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		c = &Checker{}
+	}
+	err = c.Run(filename, code)
+	if err != nil {
+		return err
+	}
+	if lang == "" {
+		lang = DetectLanguage(filename)
+	}
 
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
-	part, err := writer.CreateFormFile("solutionSource", filepath.Base(filePath))
+	part, err := writer.CreateFormFile("solutionSource", filepath.Base(filename))
 	if err != nil {
 		return err
 	}
-	_, err = io.Copy(part, file)
+	_, err = part.Write(code)
 	if err != nil {
 		return err
 	}
@@ -80,4 +96,3 @@ func SubmitSolution(client *http.Client, actionURL, filePath string) error {
 
 	return nil
 }
-
@@ -0,0 +1,67 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"aesc-client/parse"
+)
+
+func FetchSubmissions(client *http.Client, pageURL string) ([]parse.Submission, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("GET %s returned %s", pageURL, resp.Status)
+	}
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return parse.ParseSubmissions(body)
+}
+
+func KnownIDs(subs []parse.Submission) map[string]bool {
+	known := make(map[string]bool, len(subs))
+	for _, s := range subs {
+		known[s.ID] = true
+	}
+	return known
+}
+
+func WaitVerdict(ctx context.Context, client *http.Client, pageURL string, known map[string]bool, interval time.Duration) (*parse.Submission, error) {
+	if interval <= 0 {
+		interval = 3 * time.Second
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		subs, err := FetchSubmissions(client, pageURL)
+		if err != nil {
+			return nil, err
+		}
+		for i := range subs {
+			if known[subs[i].ID] {
+				continue
+			}
+			if !subs[i].Pending() {
+				return &subs[i], nil
+			}
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
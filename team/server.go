@@ -0,0 +1,66 @@
+package team
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+func Handler(b Board, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /team", func(w http.ResponseWriter, r *http.Request) {
+		members, err := b.Members()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if members == nil {
+			members = []Member{}
+		}
+		writeJSON(w, members)
+	})
+	mux.HandleFunc("GET /team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		m, err := b.Get(r.PathValue("name"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, m)
+	})
+	mux.HandleFunc("PUT /team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		var m Member
+		err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&m)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if m.Name != r.PathValue("name") {
+			http.Error(w, "member name does not match the path", http.StatusBadRequest)
+			return
+		}
+		err = b.Put(&m)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	if token == "" {
+		return mux
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "bad or missing team token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,64 @@
+package team
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlerToken(t *testing.T) {
+	srv := httptest.NewServer(Handler(&DirBoard{Dir: t.TempDir()}, "s3cret"))
+	defer srv.Close()
+	claim := &Member{Name: "alice", Claims: map[string]time.Time{"B": time.Now()}}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"no token", "", false},
+		{"wrong token", "guess", false},
+		{"token prefix", "s3cre", false},
+		{"right token", "s3cret", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &HTTPBoard{Base: srv.URL, Token: tt.token, Client: srv.Client()}
+			err := b.Put(claim)
+			if tt.ok != (err == nil) {
+				t.Fatalf("Put: %v, want ok=%v", err, tt.ok)
+			}
+			if !tt.ok && !strings.Contains(err.Error(), "401") {
+				t.Errorf("Put error %q, want a 401", err)
+			}
+			_, err = b.Members()
+			if tt.ok != (err == nil) {
+				t.Errorf("Members: %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+
+	b := &HTTPBoard{Base: srv.URL, Token: "s3cret", Client: srv.Client()}
+	members, err := b.Members()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 1 || len(members[0].Claims) != 1 {
+		t.Errorf("board after rejected writes: %+v, want alice's single claim", members)
+	}
+}
+
+func TestHandlerNoToken(t *testing.T) {
+	srv := httptest.NewServer(Handler(&DirBoard{Dir: t.TempDir()}, ""))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /team without a configured token: %s", resp.Status)
+	}
+}
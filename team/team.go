@@ -0,0 +1,216 @@
+package team
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Activity struct {
+	Problem string    `json:"problem"`
+	File    string    `json:"file,omitempty"`
+	Verdict string    `json:"verdict,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+type Member struct {
+	Name    string               `json:"name"`
+	Claims  map[string]time.Time `json:"claims"`
+	Last    *Activity            `json:"last,omitempty"`
+	Updated time.Time            `json:"updated"`
+}
+
+type Board interface {
+	Members() ([]Member, error)
+	Get(name string) (*Member, error)
+	Put(m *Member) error
+}
+
+var reMemberName = regexp.MustCompile(`^[\w.-]{1,32}$`)
+
+func ValidName(name string) bool {
+	return reMemberName.MatchString(name)
+}
+
+func Open(location, token string) (Board, error) {
+	if location == "" {
+		return nil, errors.New("no team sync location: run `aesc team set --sync DIR|URL`")
+	}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return &HTTPBoard{Base: strings.TrimRight(location, "/"), Token: token, Client: &http.Client{Timeout: 10 * time.Second}}, nil
+	}
+	return &DirBoard{Dir: location}, nil
+}
+
+func Claimers(members []Member, problem string) []string {
+	var names []string
+	for _, m := range members {
+		if _, ok := m.Claims[problem]; ok {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
+func Update(b Board, name string, f func(m *Member)) error {
+	m, err := b.Get(name)
+	if err != nil {
+		return err
+	}
+	f(m)
+	m.Updated = time.Now()
+	return b.Put(m)
+}
+
+type DirBoard struct {
+	Dir string
+}
+
+func (b *DirBoard) path(name string) string {
+	return filepath.Join(b.Dir, name+".json")
+}
+
+func (b *DirBoard) Members() ([]Member, error) {
+	files, err := filepath.Glob(filepath.Join(b.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Member
+	for _, f := range files {
+		m, err := readMember(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (b *DirBoard) Get(name string) (*Member, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("bad member name %q", name)
+	}
+	m, err := readMember(b.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Member{Name: name, Claims: map[string]time.Time{}}, nil
+	}
+	return m, err
+}
+
+func (b *DirBoard) Put(m *Member) error {
+	if !ValidName(m.Name) {
+		return fmt.Errorf("bad member name %q", m.Name)
+	}
+	err := os.MkdirAll(b.Dir, 0o755)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", b.Dir, err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode member: %w", err)
+	}
+	tmp, err := os.CreateTemp(b.Dir, "."+m.Name+"-*")
+	if err != nil {
+		return fmt.Errorf("write %s: %w", b.Dir, err)
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write %s: %w", tmp.Name(), err)
+	}
+	os.Chmod(tmp.Name(), 0o644)
+	err = os.Rename(tmp.Name(), b.path(m.Name))
+	if err != nil {
+		return fmt.Errorf("write %s: %w", b.path(m.Name), err)
+	}
+	return nil
+}
+
+func readMember(path string) (*Member, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Member
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if m.Claims == nil {
+		m.Claims = map[string]time.Time{}
+	}
+	return &m, nil
+}
+
+type HTTPBoard struct {
+	Base   string
+	Token  string
+	Client *http.Client
+}
+
+func (b *HTTPBoard) do(method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, b.Base+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.Token)
+	}
+	resp, err := b.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, req.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s returned %s: %s", method, req.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (b *HTTPBoard) Members() ([]Member, error) {
+	var out []Member
+	err := b.do("GET", "/team", nil, &out)
+	return out, err
+}
+
+func (b *HTTPBoard) Get(name string) (*Member, error) {
+	m := &Member{}
+	err := b.do("GET", "/team/"+url.PathEscape(name), nil, m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Claims == nil {
+		m.Claims = map[string]time.Time{}
+	}
+	return m, nil
+}
+
+func (b *HTTPBoard) Put(m *Member) error {
+	return b.do("PUT", "/team/"+url.PathEscape(m.Name), m, nil)
+}
@@ -42,7 +42,12 @@ func main() {
 	}
 	defer resp.Body.Close()
 
-	contests, err := parse.ParseContests(resp.Body)
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read body failed: %v\n", err)
+		os.Exit(2)
+	}
+	contests, err := parse.ParseContests(body)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ParseContests failed: %v\n", err)
 		os.Exit(2)
@@ -64,7 +69,12 @@ func main() {
 	}
 	defer resp1.Body.Close()
 
-	tasks, err := parse.ParseProblems(resp1.Body)
+	body1, err := parse.DecodeBody(resp1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read body failed: %v\n", err)
+		os.Exit(2)
+	}
+	tasks, err := parse.ParseProblems(body1)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ParseTasks failed: %v\n", err)
 		os.Exit(2)
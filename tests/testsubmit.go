@@ -43,7 +43,12 @@ func main() {
 	}
 	defer resp.Body.Close()
 
-	contests, err := parse.ParseContests(resp.Body)
+	body, err := parse.DecodeBody(resp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read body failed: %v\n", err)
+		os.Exit(2)
+	}
+	contests, err := parse.ParseContests(body)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ParseContests failed: %v\n", err)
 		os.Exit(2)
@@ -60,7 +65,12 @@ func main() {
 	}
 	defer resp1.Body.Close()
 
-	tasks, err := parse.ParseProblems(resp1.Body)
+	body1, err := parse.DecodeBody(resp1)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read body failed: %v\n", err)
+		os.Exit(2)
+	}
+	tasks, err := parse.ParseProblems(body1)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ParseTasks failed: %v\n", err)
 		os.Exit(2)
@@ -73,7 +83,7 @@ func main() {
 	actionURL := "http://server.aesc.msu.ru" + tasks[0].URL
 	filePath := "your_solution_here.cpp"
 
-	err = submit.SubmitSolution(client, actionURL, filePath)
+	err = submit.SubmitSolution(client, actionURL, filePath, nil)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "submit failed: %v\n", err)
 		os.Exit(2)
@@ -0,0 +1,86 @@
+package textdiff
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func numbered(n int, replace map[int]string) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		line, ok := replace[i]
+		if !ok {
+			line = strconv.Itoa(i)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{
+			name: "identical",
+			a:    "a\nb\n", b: "a\nb\n", context: 3,
+			want: "",
+		},
+		{
+			name: "insert only",
+			a:    "a\nb\nc\n", b: "a\nb\nX\nc\n", context: 3,
+			want: "@@ -1,3 +1,4 @@\n a\n b\n+X\n c\n",
+		},
+		{
+			name: "insert without context",
+			a:    "a\nb\nc\n", b: "a\nb\nX\nc\n", context: 0,
+			want: "@@ -2,0 +3 @@\n+X\n",
+		},
+		{
+			name: "delete only",
+			a:    "a\nb\nc\nd\n", b: "a\nd\n", context: 3,
+			want: "@@ -1,4 +1,2 @@\n a\n-b\n-c\n d\n",
+		},
+		{
+			name: "empty old side",
+			a:    "", b: "x\ny\n", context: 3,
+			want: "@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "empty new side",
+			a:    "x\n", b: "", context: 3,
+			want: "@@ -1 +0,0 @@\n-x\n",
+		},
+		{
+			name:    "two hunks",
+			a:       numbered(20, nil),
+			b:       numbered(20, map[int]string{2: "two", 15: "fifteen"}),
+			context: 3,
+			want: "@@ -1,5 +1,5 @@\n 1\n-2\n+two\n 3\n 4\n 5\n" +
+				"@@ -12,7 +12,7 @@\n 12\n 13\n 14\n-15\n+fifteen\n 16\n 17\n 18\n",
+		},
+		{
+			name:    "gap of exactly twice the context joins the hunks",
+			a:       numbered(20, nil),
+			b:       numbered(20, map[int]string{2: "two", 9: "nine"}),
+			context: 3,
+			want:    "@@ -1,12 +1,12 @@\n 1\n-2\n+two\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+nine\n 10\n 11\n 12\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.a, tt.b, tt.context)
+			want := tt.want
+			if want != "" {
+				want = "--- old\n+++ new\n" + want
+			}
+			if got != want {
+				t.Errorf("Unified:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package tui
+
+import "os"
+
+func notifyResize(c chan<- os.Signal) {}
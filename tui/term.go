@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPgUp
+	keyPgDn
+	keyHome
+	keyEnd
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyTab
+	keyCtrlC
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func makeRaw() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	_, err = stty("raw", "-echo")
+	if err != nil {
+		return nil, fmt.Errorf("enter raw mode: %w", err)
+	}
+	return func() { stty(state) }, nil
+}
+
+func termSize() (w, h int) {
+	out, err := stty("size")
+	if err == nil {
+		fmt.Sscanf(out, "%d %d", &h, &w)
+	}
+	if w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
+
+func decodeKeys(b []byte) []key {
+	var keys []key
+	seqs := []struct {
+		s string
+		c keyCode
+	}{
+		{"\x1b[A", keyUp}, {"\x1b[B", keyDown}, {"\x1b[C", keyRight}, {"\x1b[D", keyLeft},
+		{"\x1bOA", keyUp}, {"\x1bOB", keyDown}, {"\x1bOC", keyRight}, {"\x1bOD", keyLeft},
+		{"\x1b[5~", keyPgUp}, {"\x1b[6~", keyPgDn},
+		{"\x1b[H", keyHome}, {"\x1b[F", keyEnd}, {"\x1bOH", keyHome}, {"\x1bOF", keyEnd},
+		{"\x1b[1~", keyHome}, {"\x1b[4~", keyEnd},
+	}
+	for len(b) > 0 {
+		matched := false
+		for _, s := range seqs {
+			if strings.HasPrefix(string(b), s.s) {
+				keys = append(keys, key{code: s.c})
+				b = b[len(s.s):]
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		switch b[0] {
+		case 0x1b:
+			keys = append(keys, key{code: keyEsc})
+		case '\r', '\n':
+			keys = append(keys, key{code: keyEnter})
+		case 0x7f, 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case '\t':
+			keys = append(keys, key{code: keyTab})
+		case 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		default:
+			r, n := utf8.DecodeRune(b)
+			keys = append(keys, key{code: keyRune, r: r})
+			b = b[n:]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		r := []rune(s)
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+func wrap(text string, width int) []string {
+	if width < 10 {
+		width = 10
+	}
+	var out []string
+	for _, ln := range strings.Split(text, "\n") {
+		r := []rune(strings.TrimRight(ln, " "))
+		for len(r) > width {
+			cut := width
+			for i := width; i > width/2; i-- {
+				if r[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			out = append(out, string(r[:cut]))
+			r = []rune(strings.TrimLeft(string(r[cut:]), " "))
+		}
+		out = append(out, string(r))
+	}
+	return out
+}
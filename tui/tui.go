@@ -0,0 +1,416 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"aesc-client/parse"
+	"aesc-client/submit"
+)
+
+type Source interface {
+	Contests() ([]parse.Contest, error)
+	Problems(c parse.Contest) ([]parse.Problem, error)
+	Statement(p parse.Problem) (string, error)
+	Submissions(p parse.Problem) ([]parse.Submission, error)
+	Submit(p parse.Problem, file string, force bool) (warnings []string, err error)
+}
+
+const (
+	levelContests = iota
+	levelProblems
+)
+
+const (
+	focusList = iota
+	focusPane
+)
+
+const help = "↑↓ move  Enter open  Esc back  Tab focus  PgUp/PgDn scroll  s submit  v verdicts  r reload  q quit"
+
+type UI struct {
+	src   Source
+	title string
+	out   *bufio.Writer
+
+	contests []parse.Contest
+	problems []parse.Problem
+	contest  int
+	level    int
+	cursor   [2]int
+	listTop  [2]int
+
+	paneTitle string
+	pane      string
+	paneTop   int
+	focus     int
+
+	status   string
+	prompt   string
+	input    []rune
+	onSubmit func(string)
+	lastFile string
+	quit     bool
+
+	width  int
+	height int
+}
+
+func New(src Source, title string) *UI {
+	return &UI{src: src, title: title, out: bufio.NewWriter(os.Stdout)}
+}
+
+func (u *UI) Run() error {
+	restore, err := makeRaw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	io.WriteString(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	defer io.WriteString(os.Stdout, "\x1b[?25h\x1b[?1049l")
+
+	u.width, u.height = termSize()
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+	input := make(chan []byte)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			buf := make([]byte, 64)
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case input <- buf[:n]:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	u.loadContests()
+	for !u.quit {
+		u.draw()
+		select {
+		case <-resized:
+			u.width, u.height = termSize()
+		case b := <-input:
+			for _, k := range decodeKeys(b) {
+				u.handle(k)
+			}
+		case err := <-readErr:
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UI) loading(what string) {
+	u.status = "loading " + what + "..."
+	u.draw()
+}
+
+func (u *UI) loadContests() {
+	u.loading("contests")
+	cs, err := u.src.Contests()
+	if err != nil {
+		u.status = err.Error()
+		return
+	}
+	u.contests = cs
+	u.level = levelContests
+	u.status = fmt.Sprintf("%d contests", len(cs))
+}
+
+func (u *UI) loadProblems() {
+	if u.cursor[levelContests] >= len(u.contests) {
+		return
+	}
+	u.contest = u.cursor[levelContests]
+	c := u.contests[u.contest]
+	u.loading("problems of " + c.Name)
+	ps, err := u.src.Problems(c)
+	if err != nil {
+		u.status = err.Error()
+		return
+	}
+	u.problems = ps
+	u.level = levelProblems
+	u.cursor[levelProblems], u.listTop[levelProblems] = 0, 0
+	u.status = fmt.Sprintf("%s: %d problems", c.Name, len(ps))
+}
+
+func (u *UI) current() (parse.Problem, bool) {
+	if u.level != levelProblems || u.cursor[levelProblems] >= len(u.problems) {
+		return parse.Problem{}, false
+	}
+	return u.problems[u.cursor[levelProblems]], true
+}
+
+func (u *UI) showStatement() {
+	p, ok := u.current()
+	if !ok {
+		return
+	}
+	u.loading("statement of " + p.Name)
+	text, err := u.src.Statement(p)
+	if err != nil {
+		u.status = err.Error()
+		return
+	}
+	u.paneTitle, u.pane, u.paneTop = p.Name, text, 0
+	u.status = ""
+}
+
+func (u *UI) showSubmissions() {
+	p, ok := u.current()
+	if !ok {
+		return
+	}
+	u.loading("submissions of " + p.Name)
+	subs, err := u.src.Submissions(p)
+	if err != nil {
+		u.status = err.Error()
+		return
+	}
+	var b strings.Builder
+	if len(subs) == 0 {
+		b.WriteString("no submissions yet\n")
+	}
+	for _, s := range subs {
+		ts := ""
+		if !s.Time.IsZero() {
+			ts = s.Time.Format("15:04:05")
+		}
+		fmt.Fprintf(&b, "%-6s %-8s %-10s %s", s.ID, ts, s.Language, s.Verdict)
+		if s.Test > 0 && !s.Accepted() && !s.Pending() {
+			fmt.Fprintf(&b, " (test %d)", s.Test)
+		}
+		if s.Score > 0 {
+			fmt.Fprintf(&b, " [%d]", s.Score)
+		}
+		b.WriteByte('\n')
+	}
+	u.paneTitle, u.pane, u.paneTop = p.Name+" — submissions", b.String(), 0
+	u.status = ""
+}
+
+func (u *UI) startSubmit() {
+	p, ok := u.current()
+	if !ok {
+		u.status = "select a problem first"
+		return
+	}
+	u.prompt = "submit " + p.Short + " file: "
+	u.input = []rune(u.lastFile)
+	u.onSubmit = func(file string) {
+		if file == "" {
+			return
+		}
+		u.lastFile = file
+		u.submit(p, file, false)
+	}
+}
+
+func (u *UI) submit(p parse.Problem, file string, force bool) {
+	u.loading("submitting " + file)
+	warnings, err := u.src.Submit(p, file, force)
+	if errors.Is(err, submit.ErrNotConfirmed) {
+		u.status = ""
+		u.prompt = strings.TrimPrefix(err.Error(), submit.ErrNotConfirmed.Error()+": ") + ". Submit anyway? [y/N] "
+		u.input = nil
+		u.onSubmit = func(ans string) {
+			if strings.HasPrefix(strings.ToLower(ans), "y") {
+				u.submit(p, file, true)
+			}
+		}
+		return
+	}
+	if err != nil {
+		u.status = err.Error()
+		return
+	}
+	u.showSubmissions()
+	u.status = "submitted " + file + ", press v to refresh verdicts"
+	if len(warnings) > 0 {
+		u.status += "; warning: " + strings.Join(warnings, "; ")
+	}
+}
+
+func (u *UI) handle(k key) {
+	if u.prompt != "" {
+		u.handlePrompt(k)
+		return
+	}
+	lvl := u.level
+	n := len(u.contests)
+	if lvl == levelProblems {
+		n = len(u.problems)
+	}
+	_, _, bodyH := u.layout()
+	switch {
+	case k.code == keyCtrlC, k.code == keyRune && k.r == 'q':
+		u.quit = true
+	case k.code == keyTab:
+		u.focus = 1 - u.focus
+	case u.focus == focusPane && (k.code == keyUp || k.code == keyRune && k.r == 'k'):
+		u.scroll(-1)
+	case u.focus == focusPane && (k.code == keyDown || k.code == keyRune && k.r == 'j'):
+		u.scroll(1)
+	case k.code == keyPgUp:
+		u.scroll(-(bodyH - 1))
+	case k.code == keyPgDn, k.code == keyRune && k.r == ' ':
+		u.scroll(bodyH - 1)
+	case k.code == keyUp, k.code == keyRune && k.r == 'k':
+		if u.cursor[lvl] > 0 {
+			u.cursor[lvl]--
+		}
+	case k.code == keyDown, k.code == keyRune && k.r == 'j':
+		if u.cursor[lvl] < n-1 {
+			u.cursor[lvl]++
+		}
+	case k.code == keyHome:
+		u.cursor[lvl] = 0
+	case k.code == keyEnd:
+		u.cursor[lvl] = max(n-1, 0)
+	case k.code == keyEnter, k.code == keyRight, k.code == keyRune && k.r == 'l':
+		if lvl == levelContests {
+			u.loadProblems()
+		} else {
+			u.showStatement()
+		}
+	case k.code == keyEsc, k.code == keyBackspace, k.code == keyLeft, k.code == keyRune && k.r == 'h':
+		if u.focus == focusPane {
+			u.focus = focusList
+		} else if lvl == levelProblems {
+			u.level = levelContests
+		}
+	case k.code == keyRune && k.r == 's':
+		u.startSubmit()
+	case k.code == keyRune && k.r == 'v':
+		u.showSubmissions()
+	case k.code == keyRune && k.r == 'r':
+		if lvl == levelContests {
+			u.loadContests()
+		} else {
+			u.cursor[levelContests] = u.contest
+			cur := u.cursor[levelProblems]
+			u.loadProblems()
+			u.cursor[levelProblems] = min(cur, max(len(u.problems)-1, 0))
+		}
+	}
+}
+
+func (u *UI) handlePrompt(k key) {
+	switch k.code {
+	case keyEsc, keyCtrlC:
+		u.prompt, u.onSubmit = "", nil
+	case keyEnter:
+		f := u.onSubmit
+		value := strings.TrimSpace(string(u.input))
+		u.prompt, u.onSubmit = "", nil
+		if f != nil {
+			f(value)
+		}
+	case keyBackspace:
+		if len(u.input) > 0 {
+			u.input = u.input[:len(u.input)-1]
+		}
+	case keyRune:
+		u.input = append(u.input, k.r)
+	}
+}
+
+func (u *UI) scroll(d int) {
+	_, paneW, bodyH := u.layout()
+	total := len(wrap(u.pane, paneW))
+	u.paneTop = max(0, min(u.paneTop+d, total-bodyH))
+}
+
+func (u *UI) layout() (listW, paneW, bodyH int) {
+	w, h := u.width, u.height
+	listW = max(24, w/3)
+	if listW > w-20 {
+		listW = w / 2
+	}
+	return listW, w - listW - 1, h - 2
+}
+
+func (u *UI) draw() {
+	w := u.width
+	listW, paneW, bodyH := u.layout()
+
+	var items []string
+	lvl := u.level
+	if lvl == levelContests {
+		for _, c := range u.contests {
+			items = append(items, c.Name)
+		}
+	} else {
+		for _, p := range u.problems {
+			mark := "  "
+			if p.Solved {
+				mark = "✓ "
+			} else if p.Attempts > 0 {
+				mark = "✗ "
+			}
+			items = append(items, mark+p.Name)
+		}
+	}
+	if u.cursor[lvl] < u.listTop[lvl] {
+		u.listTop[lvl] = u.cursor[lvl]
+	}
+	if u.cursor[lvl] >= u.listTop[lvl]+bodyH {
+		u.listTop[lvl] = u.cursor[lvl] - bodyH + 1
+	}
+	pane := wrap(u.pane, paneW)
+
+	o := u.out
+	o.WriteString("\x1b[H")
+	header := " aesc " + u.title
+	if lvl == levelProblems && u.contest < len(u.contests) {
+		header += " › " + u.contests[u.contest].Name
+	}
+	if u.paneTitle != "" {
+		header += " › " + u.paneTitle
+	}
+	fmt.Fprintf(o, "\x1b[7m%s\x1b[0m\r\n", fit(header, w))
+	for i := 0; i < bodyH; i++ {
+		idx := u.listTop[lvl] + i
+		cell := ""
+		if idx < len(items) {
+			cell = items[idx]
+		}
+		cell = fit(" "+cell, listW)
+		if idx == u.cursor[lvl] && idx < len(items) {
+			if u.focus == focusList {
+				cell = "\x1b[7m" + cell + "\x1b[0m"
+			} else {
+				cell = "\x1b[1m" + cell + "\x1b[0m"
+			}
+		}
+		line := ""
+		if j := u.paneTop + i; j < len(pane) {
+			line = pane[j]
+		}
+		fmt.Fprintf(o, "%s│%s\r\n", cell, fit(line, paneW))
+	}
+	bottom := u.status
+	if u.prompt != "" {
+		bottom = u.prompt + string(u.input) + "█"
+	} else if bottom == "" {
+		bottom = help
+	}
+	fmt.Fprintf(o, "\x1b[7m%s\x1b[0m", fit(bottom, w))
+	o.Flush()
+}
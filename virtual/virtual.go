@@ -0,0 +1,170 @@
+package virtual
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"aesc-client/parse"
+	"aesc-client/submit"
+)
+
+const PenaltyPerAttempt = 20
+
+type Session struct {
+	ID       string    `json:"id"`
+	Contest  string    `json:"contest"`
+	Name     string    `json:"name,omitempty"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Finished bool      `json:"finished,omitempty"`
+}
+
+func New(contest, name string, start time.Time, d time.Duration) *Session {
+	var id [6]byte
+	rand.Read(id[:])
+	return &Session{ID: hex.EncodeToString(id[:]), Contest: contest, Name: name, Start: start, End: start.Add(d)}
+}
+
+func Load(path string) (*Session, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	var s Session
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Session) Save(path string) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode virtual contest: %w", err)
+	}
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *Session) Active(now time.Time) bool {
+	return !s.Finished && now.Before(s.End)
+}
+
+func (s *Session) Elapsed(t time.Time) time.Duration {
+	return min(max(t.Sub(s.Start), 0), s.End.Sub(s.Start))
+}
+
+func (s *Session) Tag(e *submit.Entry) bool {
+	if !s.Active(e.Time) || e.Contest != s.Contest {
+		return false
+	}
+	e.Virtual = s.ID
+	e.VirtualSec = int64(s.Elapsed(e.Time) / time.Second)
+	return true
+}
+
+type ProblemResult struct {
+	Problem  string
+	Solved   bool
+	Attempts int
+	Minute   int
+	Score    int
+}
+
+type Result struct {
+	Solved   int
+	Penalty  int
+	Score    int
+	Pending  int
+	Problems []ProblemResult
+	Place    int
+	Teams    int
+	Scored   bool
+}
+
+func countsAsAttempt(e submit.Entry) bool {
+	v := strings.ToLower(e.Verdict)
+	return !strings.Contains(v, "compil") && !strings.Contains(v, "компиляц")
+}
+
+func (s *Session) Compute(entries []submit.Entry, final *parse.Standings) Result {
+	var mine []submit.Entry
+	for _, e := range entries {
+		if e.Virtual == s.ID {
+			mine = append(mine, e)
+		}
+	}
+	sort.SliceStable(mine, func(i, j int) bool { return mine[i].VirtualSec < mine[j].VirtualSec })
+	var r Result
+	byProblem := map[string]*ProblemResult{}
+	var order []string
+	for _, e := range mine {
+		p, ok := byProblem[e.Problem]
+		if !ok {
+			p = &ProblemResult{Problem: e.Problem}
+			byProblem[e.Problem] = p
+			order = append(order, e.Problem)
+		}
+		if !e.Final() {
+			r.Pending++
+			continue
+		}
+		p.Score = max(p.Score, e.Score)
+		if p.Solved || !countsAsAttempt(e) {
+			continue
+		}
+		p.Attempts++
+		if e.Accepted {
+			p.Solved = true
+			p.Minute = int(e.VirtualSec / 60)
+		}
+	}
+	sort.Strings(order)
+	for _, name := range order {
+		p := byProblem[name]
+		r.Problems = append(r.Problems, *p)
+		r.Score += p.Score
+		if p.Solved {
+			r.Solved++
+			r.Penalty += p.Minute + PenaltyPerAttempt*(p.Attempts-1)
+		}
+	}
+	if final == nil {
+		return r
+	}
+	for _, row := range final.Rows {
+		if row.Score > 0 {
+			r.Scored = true
+		}
+	}
+	r.Teams = len(final.Rows)
+	r.Place = 1
+	for _, row := range final.Rows {
+		switch {
+		case r.Scored && row.Score > r.Score:
+			r.Place++
+		case !r.Scored && (row.Solved > r.Solved || row.Solved == r.Solved && row.Penalty < r.Penalty):
+			r.Place++
+		}
+	}
+	return r
+}
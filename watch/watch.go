@@ -0,0 +1,37 @@
+package watch
+
+import (
+	"context"
+	"time"
+)
+
+func Changes(ctx context.Context, path string, debounce time.Duration) (<-chan struct{}, error) {
+	raw, err := watchFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan struct{})
+	go func() {
+		defer close(out)
+		var fire <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-raw:
+				if !ok {
+					return
+				}
+				fire = time.After(debounce)
+			case <-fire:
+				fire = nil
+				select {
+				case out <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
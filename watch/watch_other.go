@@ -0,0 +1,40 @@
+//go:build !linux
+
+package watch
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+func watchFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	last := st.ModTime()
+	out := make(chan struct{}, 1)
+	go func() {
+		defer close(out)
+		t := time.NewTicker(500 * time.Millisecond)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			st, err := os.Stat(path)
+			if err != nil || !st.ModTime().After(last) {
+				continue
+			}
+			last = st.ModTime()
+			select {
+			case out <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return out, nil
+}
@@ -0,0 +1,134 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aesc-client/parse"
+)
+
+const FileName = ".aesc.json"
+
+type Manifest struct {
+	Profile string            `json:"profile,omitempty"`
+	Contest string            `json:"contest,omitempty"`
+	Team    string            `json:"team,omitempty"`
+	Files   map[string]string `json:"files"`
+
+	dir string
+}
+
+func New(dir string) *Manifest {
+	return &Manifest{Files: map[string]string{}, dir: dir}
+}
+
+func Find(start string) (*Manifest, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		m, err := Load(dir)
+		if err == nil {
+			return m, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("no %s found above %s: %w", FileName, start, os.ErrNotExist)
+		}
+		dir = parent
+	}
+}
+
+func Load(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, FileName)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := New(dir)
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return m, nil
+}
+
+func (m *Manifest) Dir() string {
+	return m.dir
+}
+
+func (m *Manifest) TeamLocation() string {
+	if m.Team == "" || strings.Contains(m.Team, "://") || filepath.IsAbs(m.Team) {
+		return m.Team
+	}
+	return filepath.Join(m.dir, m.Team)
+}
+
+func (m *Manifest) Save() error {
+	path := filepath.Join(m.dir, FileName)
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	err = os.WriteFile(path, append(b, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (m *Manifest) Map(file, problem string) error {
+	rel, err := m.rel(file)
+	if err != nil {
+		return err
+	}
+	m.Files[rel] = problem
+	return nil
+}
+
+func (m *Manifest) Ref(file string) (string, bool) {
+	rel, err := m.rel(file)
+	if err != nil {
+		return "", false
+	}
+	ref, ok := m.Files[rel]
+	return ref, ok
+}
+
+func (m *Manifest) rel(file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(m.dir, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is outside the workspace %s", file, m.dir)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+func Guess(file string, problems []parse.Problem) (*parse.Problem, bool) {
+	stem := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	for _, cut := range []string{"", "_", "-", "."} {
+		head := stem
+		if cut != "" {
+			head, _, _ = strings.Cut(stem, cut)
+		}
+		for i := range problems {
+			if problems[i].Short != "" && strings.EqualFold(problems[i].Short, head) {
+				return &problems[i], true
+			}
+		}
+	}
+	return nil, false
+}